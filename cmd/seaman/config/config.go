@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
@@ -42,6 +43,12 @@ type Config struct {
 	GitHubWebhook GitHubWebhookConfig `json:"githubWebhook" validate:"required"`
 	Release       ReleaseConfig       `json:"release" validate:"required"`
 	Emtec         EmtecConfig         `json:"emtec"`
+	Audit         AuditConfig         `json:"audit"`
+	AdminServer   AdminServerConfig   `json:"adminServer"`
+	Tracing       TracingConfig       `json:"tracing"`
+
+	// ShutdownGracePeriodSeconds is the time to wait for in-flight operations on SIGTERM
+	ShutdownGracePeriodSeconds int `json:"shutdownGracePeriodSeconds" default:"30" validate:"min=0"`
 }
 
 // for each external service
@@ -49,6 +56,44 @@ type Config struct {
 type SlackConfig struct {
 	BotToken string `json:"botToken" validate:"required"`
 	AppToken string `json:"appToken" validate:"required"`
+
+	// HandlerTimeoutSeconds is the deadline of handling each command or interaction
+	HandlerTimeoutSeconds int `json:"handlerTimeoutSeconds" default:"300" validate:"min=1"`
+
+	// Reply decides where the replies to commands are posted
+	Reply SlackReplyConfig `json:"reply"`
+	// Language decides the language of messages
+	Language SlackLanguageConfig `json:"language"`
+	// Callback is the setting for signing the values of buttons and menus
+	Callback SlackCallbackConfig `json:"callback"`
+}
+
+type SlackReplyConfig struct {
+	// Thread makes the replies to all commands go to the thread of the mention
+	Thread bool `json:"thread"`
+	// ThreadChannels is the list of channel IDs in which the replies go to the thread
+	ThreadChannels []string `json:"threadChannels"`
+	// ThreadCommands is the list of commands (e.g. "release") or their prefixes (e.g. "emtec")
+	// whose replies go to the thread
+	ThreadCommands []string `json:"threadCommands"`
+	// Ephemeral makes errors and the help visible only to the requester
+	Ephemeral bool `json:"ephemeral"`
+}
+
+type SlackLanguageConfig struct {
+	// Default is the language used if neither Channels nor UserLocale decides it (ja or en)
+	Default string `json:"default" default:"ja" validate:"oneof=ja en"`
+	// Channels maps channel IDs to their languages, which take precedence over UserLocale
+	Channels map[string]string `json:"channels" validate:"dive,oneof=ja en"`
+	// UserLocale uses the locale of the Slack user if it is ja or en
+	UserLocale bool `json:"userLocale"`
+}
+
+type SlackCallbackConfig struct {
+	// SigningKey is the key of HMAC (a random key is generated on startup if empty)
+	SigningKey string `json:"signingKey"`
+	// TTLSeconds is the duration during which buttons and menus can be used
+	TTLSeconds int `json:"ttlSeconds" default:"86400" validate:"min=60"`
 }
 
 type GitHubConfig struct {
@@ -57,8 +102,74 @@ type GitHubConfig struct {
 }
 
 type GitHubWebhookConfig struct {
-	BindAddr string `json:"bindAddr" validate:"required"`
-	Secret   string `json:"secret" validate:"required"`
+	BindAddr string                    `json:"bindAddr" validate:"required"`
+	Secret   string                    `json:"secret" validate:"required"`
+	Queue    GitHubWebhookQueueConfig  `json:"queue"`
+	Replay   GitHubWebhookReplayConfig `json:"replay"`
+	// Commands is the authorization setting for each command (e.g. "/SEPARATE")
+	Commands map[string]GitHubWebhookCommandConfig `json:"commands" validate:"dive"`
+	// Language is the language of comments (ja or en)
+	Language string `json:"language" default:"ja" validate:"oneof=ja en"`
+	// Languages maps "<org>/<repo>" to the language of comments in the repository
+	Languages map[string]string `json:"languages" validate:"dive,oneof=ja en"`
+}
+
+type GitHubWebhookQueueConfig struct {
+	// Workers is the number of commands processed concurrently
+	Workers int `json:"workers" default:"4" validate:"min=1"`
+	// Dir is the directory for persisting queued commands (in-memory if empty)
+	Dir                  string `json:"dir"`
+	MaxRetries           int    `json:"maxRetries" default:"3" validate:"min=0"`
+	RetryIntervalSeconds int    `json:"retryIntervalSeconds" default:"5" validate:"min=1"`
+}
+
+type GitHubWebhookCommandConfig struct {
+	// AuthorAssociations is the list of allowed author associations (e.g. OWNER, MEMBER)
+	AuthorAssociations []string `json:"authorAssociations" validate:"dive,oneof=OWNER MEMBER COLLABORATOR CONTRIBUTOR FIRST_TIME_CONTRIBUTOR FIRST_TIMER MANNEQUIN NONE"`
+	// Teams is the list of "<org>/<team-slug>" whose members are allowed
+	Teams []string `json:"teams" validate:"dive,contains=/"`
+	// Repositories is the list of "<org>/<repo>" on which the command can be run
+	Repositories []string `json:"repositories" validate:"dive,contains=/"`
+}
+
+type GitHubWebhookReplayConfig struct {
+	// Capacity is the number of recent deliveries kept for replay
+	Capacity int `json:"capacity" default:"100" validate:"min=0"`
+	// AdminToken is the bearer token for admin endpoint (disabled if empty)
+	AdminToken string `json:"adminToken"`
+}
+
+type AuditConfig struct {
+	// Capacity is the number of recent entries shown by "audit" command
+	Capacity int `json:"capacity" default:"100" validate:"min=1"`
+	// File is the path of JSON Lines file to which entries are appended (optional)
+	File string `json:"file"`
+	// Channel is the Slack channel ID to which entries are posted (optional)
+	Channel string `json:"channel"`
+}
+
+type AdminServerConfig struct {
+	// BindAddr is the address serving /healthz, /readyz and /metrics (disabled if empty)
+	BindAddr string `json:"bindAddr"`
+	// CheckTimeoutSeconds is the timeout of each readiness check
+	CheckTimeoutSeconds int `json:"checkTimeoutSeconds" default:"5" validate:"min=1"`
+}
+
+type TracingConfig struct {
+	// Endpoint is the OTLP gRPC endpoint to which spans are exported (disabled if empty)
+	Endpoint string `json:"endpoint"`
+	Insecure bool   `json:"insecure"`
+	// SampleRatio is the ratio of sampled traces (1 if unset). It is a pointer
+	// because the default of struct tags would replace explicit 0.
+	SampleRatio *float64 `json:"sampleRatio" validate:"omitempty,min=0,max=1"`
+}
+
+// SampleRatioOrDefault returns SampleRatio, which is 1 if unset
+func (c TracingConfig) SampleRatioOrDefault() float64 {
+	if c.SampleRatio == nil {
+		return 1
+	}
+	return *c.SampleRatio
 }
 
 // for each subcommand
@@ -73,5 +184,76 @@ type ReleaseTarget struct {
 }
 
 type EmtecConfig struct {
-	EndpointUrl string `json:"endpointUrl"`
+	// EndpointUrl and TLS are of the event named "default" (use Events for multiple events)
+	EndpointUrl string         `json:"endpointUrl"`
+	TLS         EmtecTLSConfig `json:"tls"`
+	// Events is the list of EMTEC-ECU for each event (conference)
+	Events      []EmtecEventConfig     `json:"events" validate:"unique=Name,dive"`
+	Poller      EmtecPollerConfig      `json:"poller"`
+	SceneSwitch EmtecSceneSwitchConfig `json:"sceneSwitch"`
+	// Timetable is the list of scheduled toggles of automation
+	Timetable []EmtecTimetableConfig `json:"timetable" validate:"dive"`
+	// TimeZone is the IANA time zone in which "HH:MM" of schedules is read and times are shown
+	TimeZone string `json:"timeZone" default:"Asia/Tokyo" validate:"timezone"`
+	// TimeoutSeconds is the deadline of each call
+	TimeoutSeconds int `json:"timeoutSeconds" default:"10" validate:"min=1"`
+	// MaxAttempts is the max number of attempts of idempotent calls (no retry if 1)
+	MaxAttempts int `json:"maxAttempts" default:"3" validate:"min=1,max=5"`
+	// KeepaliveSeconds is the interval of keepalive pings (disabled if 0)
+	KeepaliveSeconds int `json:"keepaliveSeconds" validate:"min=0"`
+}
+
+type EmtecEventConfig struct {
+	Name        string         `json:"name" validate:"required,excludes=__"`
+	EndpointUrl string         `json:"endpointUrl" validate:"required"`
+	TLS         EmtecTLSConfig `json:"tls"`
+	// Channels is the list of channel IDs in which this event is used without --event
+	Channels []string `json:"channels"`
+}
+
+// AllEvents returns Events including the "default" event specified by EndpointUrl
+func (c EmtecConfig) AllEvents() []EmtecEventConfig {
+	if c.EndpointUrl == "" {
+		return c.Events
+	}
+	return append([]EmtecEventConfig{{Name: "default", EndpointUrl: c.EndpointUrl, TLS: c.TLS}}, c.Events...)
+}
+
+type EmtecTLSConfig struct {
+	Enabled bool `json:"enabled"`
+	// CAFile is the CA certificate for verifying EMTEC-ECU (system roots if empty)
+	CAFile string `json:"caFile"`
+	// CertFile and KeyFile are the client certificate for mTLS (optional)
+	CertFile           string `json:"certFile" validate:"required_with=KeyFile"`
+	KeyFile            string `json:"keyFile" validate:"required_with=CertFile"`
+	ServerName         string `json:"serverName"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+}
+
+type EmtecTimetableConfig struct {
+	// Event is the event name (optional if Channel is mapped to the event or only one event is configured)
+	Event string `json:"event"`
+	// Track is the ID or the name of the track
+	Track  string `json:"track" validate:"required"`
+	Action string `json:"action" validate:"oneof=enable-track disable-track"`
+	// At is the time in RFC3339 (e.g. 2023-12-11T13:00:00+09:00)
+	At time.Time `json:"at" validate:"required"`
+	// Channel is the channel ID to which the execution is reported
+	Channel string `json:"channel" validate:"required"`
+}
+
+type EmtecSceneSwitchConfig struct {
+	// ConfirmTracks is the list of track names or IDs ("*" for all) which show
+	// the confirm dialog on switching the scene
+	ConfirmTracks []string `json:"confirmTracks" default:"[\"*\"]"`
+	// UndoWindowSeconds is the duration during which switching the scene can be undone (disabled if 0).
+	// The scene is switched after the duration because EMTEC-ECU cannot move the scene back.
+	UndoWindowSeconds int `json:"undoWindowSeconds" validate:"min=0,max=60"`
+}
+
+type EmtecPollerConfig struct {
+	// IntervalSeconds is the interval of polling EMTEC (disabled if 0)
+	IntervalSeconds int `json:"intervalSeconds" validate:"min=0"`
+	// NotificationChannel is the channel ID to which changes are posted (optional)
+	NotificationChannel string `json:"notificationChannel"`
 }
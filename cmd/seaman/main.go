@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// embed the time zone database because the runtime image has no tzdata
+	_ "time/tzdata"
 
+	"github.com/slack-go/slack"
 	"golang.org/x/exp/slog"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/cloudnativedaysjp/seaman/cmd/seaman/config"
+	"github.com/cloudnativedaysjp/seaman/internal/admin"
 	"github.com/cloudnativedaysjp/seaman/internal/githubwh"
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot"
+	"github.com/cloudnativedaysjp/seaman/internal/tracing"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
 )
 
@@ -20,7 +30,10 @@ func main() {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	eg, ctx := errgroup.WithContext(context.Background())
+	// stop gracefully on SIGTERM (e.g. from Kubernetes) or SIGINT
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	eg, ctx := errgroup.WithContext(ctx)
 
 	// for Logger
 	loggerOpts := &slog.HandlerOptions{
@@ -42,11 +55,72 @@ func main() {
 	}
 	ctx = log.IntoContext(ctx, slog.New(slog.NewJSONHandler(os.Stdout, loggerOpts)))
 
+	// for tracing
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Endpoint:    conf.Tracing.Endpoint,
+		Insecure:    conf.Tracing.Insecure,
+		SampleRatio: conf.Tracing.SampleRatioOrDefault(),
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// for audit log
+	auditor, err := newAuditLogger(conf)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	// launch
-	eg.Go(func() error { return slackbot.Run(ctx, conf) })
-	eg.Go(func() error { return githubwh.Run(ctx, conf) })
-	if err := eg.Wait(); err != nil {
+	webhookServer, err := githubwh.New(ctx, conf, auditor)
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	var adminServer *admin.Server
+	if addr := conf.AdminServer.BindAddr; addr != "" {
+		adminServer = admin.New(addr,
+			time.Duration(conf.AdminServer.CheckTimeoutSeconds)*time.Second,
+			time.Duration(conf.ShutdownGracePeriodSeconds)*time.Second)
+	}
+	eg.Go(func() error { return slackbot.Run(ctx, conf, webhookServer.Replayer(), auditor, adminServer) })
+	eg.Go(func() error { return webhookServer.Run(ctx) })
+	eg.Go(func() error { return adminServer.Run(ctx) })
+	err = eg.Wait()
+
+	// flush remaining audit entries and spans
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	if err := auditor.Close(flushCtx); err != nil {
+		fmt.Println(err)
+	}
+	if err := shutdownTracing(flushCtx); err != nil {
+		fmt.Println(err)
+	}
+	cancel()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func newAuditLogger(conf *config.Config) (*audit.Logger, error) {
+	auditor := audit.New(conf.Audit.Capacity)
+	if conf.Audit.File != "" {
+		sink, err := audit.NewFileSink(conf.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+		auditor.WithSink(sink)
+	}
+	if conf.Audit.Channel != "" {
+		sc, err := infra_slack.NewSlackClientImpl(*slack.New(conf.Slack.BotToken,
+			slack.OptionHTTPClient(infra_slack.NewHTTPClient())))
+		if err != nil {
+			return nil, err
+		}
+		auditor.WithSink(audit.NewSlackSink(sc, conf.Audit.Channel))
+	}
+	return auditor, nil
 }
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+// CheckFunc returns error if the dependency is not ready
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Server is server exposing /healthz, /readyz and /metrics.
+// All methods of nil Server do nothing so that it can be disabled.
+type Server struct {
+	bindAddr     string
+	checkTimeout time.Duration
+	gracePeriod  time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+// New returns Server listening on bindAddr.
+// Each readiness check is regarded as failed if it takes longer than checkTimeout.
+func New(bindAddr string, checkTimeout, gracePeriod time.Duration) *Server {
+	return &Server{bindAddr: bindAddr, checkTimeout: checkTimeout, gracePeriod: gracePeriod}
+}
+
+// AddReadinessCheck adds the check for /readyz
+func (s *Server) AddReadinessCheck(name string, fn CheckFunc) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, check{name, fn})
+}
+
+// Handler returns http.Handler of the server
+func (s *Server) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Get("/healthz", s.healthz)
+	r.Get("/readyz", s.readyz)
+	r.Handle("/metrics", metrics.Handler())
+	return r
+}
+
+// Run is entrypoint for running the server. It stops when ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	server := &http.Server{Addr: s.bindAddr, Handler: s.Handler()}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.gracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Warn(fmt.Sprintf("failed to shut down admin server: %v", err))
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// readyz runs all checks concurrently and responds 503 if any of them fails
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	checks := s.checks
+	s.mu.RUnlock()
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), s.checkTimeout)
+			defer cancel()
+			results[i] = c.fn(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	var body strings.Builder
+	for i, c := range checks {
+		if err := results[i]; err != nil {
+			status = http.StatusServiceUnavailable
+			fmt.Fprintf(&body, "%s: %v\n", c.name, err)
+		} else {
+			fmt.Fprintf(&body, "%s: ok\n", c.name)
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprint(w, body.String())
+}
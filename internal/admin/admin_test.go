@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServer_readyz(t *testing.T) {
+	t.Parallel()
+	get := func(t *testing.T, s *Server, path string) (int, string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	t.Run("ready if all checks succeed", func(t *testing.T) {
+		s := New("", time.Second, 0)
+		s.AddReadinessCheck("slack", func(context.Context) error { return nil })
+		s.AddReadinessCheck("github", func(context.Context) error { return nil })
+		code, body := get(t, s, "/readyz")
+		if code != http.StatusOK {
+			t.Errorf("got %d, want %d", code, http.StatusOK)
+		}
+		if diff := cmp.Diff("slack: ok\ngithub: ok\n", body); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("not ready if any check fails", func(t *testing.T) {
+		s := New("", time.Second, 0)
+		s.AddReadinessCheck("slack", func(context.Context) error { return nil })
+		s.AddReadinessCheck("github", func(context.Context) error { return errors.New("unauthorized") })
+		code, body := get(t, s, "/readyz")
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("got %d, want %d", code, http.StatusServiceUnavailable)
+		}
+		if diff := cmp.Diff("slack: ok\ngithub: unauthorized\n", body); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("check is failed by timeout", func(t *testing.T) {
+		s := New("", 10*time.Millisecond, 0)
+		s.AddReadinessCheck("emtec", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		code, body := get(t, s, "/readyz")
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("got %d, want %d", code, http.StatusServiceUnavailable)
+		}
+		if diff := cmp.Diff("emtec: context deadline exceeded\n", body); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("healthz does not run checks", func(t *testing.T) {
+		s := New("", time.Second, 0)
+		s.AddReadinessCheck("github", func(context.Context) error { return errors.New("unauthorized") })
+		if code, _ := get(t, s, "/healthz"); code != http.StatusOK {
+			t.Errorf("got %d, want %d", code, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package githubwh
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	chi "github.com/go-chi/chi/v5"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/cosme"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+func requireBearerToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func listDeliveries(replayer Replayer) http.HandlerFunc {
+	type delivery struct {
+		ID         string    `json:"id"`
+		Event      string    `json:"event"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		result := []delivery{}
+		for _, d := range replayer.Deliveries() {
+			result = append(result, delivery{d.ID, d.Event, d.ReceivedAt})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
+
+func replayDelivery(replayer Replayer, auditor *audit.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deliveryId := chi.URLParam(r, "deliveryId")
+		enqueued, err := replayer.Replay(r.Context(), deliveryId)
+		auditor.Record(r.Context(), audit.Entry{
+			Source: audit.SourceAdmin, Actor: r.RemoteAddr, Action: "webhook.replay",
+			Target: deliveryId, Outcome: audit.OutcomeOf(err), Detail: fmt.Sprintf("enqueued=%t", enqueued),
+		})
+		if errors.Is(err, cosme.ErrDeliveryNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			log.FromContext(r.Context()).Error(err.Error(), log.KeyDetail, err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"deliveryId": deliveryId, "enqueued": enqueued})
+	}
+}
@@ -0,0 +1,43 @@
+package githubwh
+
+import "github.com/cloudnativedaysjp/seaman/pkg/i18n"
+
+// keys of catalog. The descriptions of commands and flags are keyed by
+// "<command>" and "<command> --<flag>" respectively.
+const (
+	msgHelpHeader = "help.header"
+	msgSeparated  = "separate.separated"
+)
+
+// catalog is the messages commented on GitHub
+var catalog = i18n.Catalog{
+	msgHelpHeader: {
+		i18n.Japanese: "以下のコマンドが存在します。",
+		i18n.English:  "The following commands are available.",
+	},
+	msgSeparated: {
+		i18n.Japanese: "以下の PR に分割しました\n%s\n**この PR の代わりにこれらをマージしてください。**",
+		i18n.English:  "separated to the following PRs\n%s\n**Please merge them instead of this PR.**",
+	},
+	"/HELP": {
+		i18n.Japanese: "コマンドの一覧を表示します",
+		i18n.English:  "Shows the list of commands",
+	},
+	"/SEPARATE": {
+		i18n.Japanese: "Renovate により作成された PR を環境ごとの PR に分割します",
+		i18n.English:  "Separates the PR created by Renovate into the PRs for each environment",
+	},
+	"/SEPARATE --envs": {
+		i18n.Japanese: "分割する環境",
+		i18n.English:  "environments to separate into",
+	},
+}
+
+// description returns the description of the command or the flag in lang,
+// or defaultDescription if it is not in catalog
+func description(lang i18n.Lang, key, defaultDescription string) string {
+	if _, ok := catalog[key]; !ok {
+		return defaultDescription
+	}
+	return catalog.Sprintf(lang, key)
+}
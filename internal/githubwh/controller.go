@@ -4,18 +4,44 @@ import (
 	"github.com/cloudnativedaysjp/seaman/internal/infra/gitcommand"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
 	"github.com/cloudnativedaysjp/seaman/internal/service"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
 type Controller struct {
 	gitcommand gitcommand.GitCommandClient
 	githubapi  githubapi.GitHubApiClient
 	service    service.GitHubIface
+	audit      *audit.Logger
+
+	lang      i18n.Lang
+	repoLangs map[string]i18n.Lang
 }
 
 func NewController(
 	gitcommand gitcommand.GitCommandClient,
 	githubapi githubapi.GitHubApiClient,
+	auditor *audit.Logger,
 ) *Controller {
 	service := service.NewGitHubService(gitcommand, githubapi)
-	return &Controller{gitcommand, githubapi, service}
+	return &Controller{gitcommand: gitcommand, githubapi: githubapi, service: service, audit: auditor,
+		lang: i18n.Default}
+}
+
+// WithLanguage sets the language of comments. repoLangs maps "<org>/<repo>" to
+// the language used in the repository instead of lang.
+func (c *Controller) WithLanguage(lang i18n.Lang, repoLangs map[string]i18n.Lang) *Controller {
+	if lang != "" {
+		c.lang = lang
+	}
+	c.repoLangs = repoLangs
+	return c
+}
+
+// language returns the language of comments in the repository ("<org>/<repo>")
+func (c Controller) language(fullName string) i18n.Lang {
+	if lang, ok := c.repoLangs[fullName]; ok {
+		return lang
+	}
+	return c.lang
 }
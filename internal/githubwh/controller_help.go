@@ -2,14 +2,43 @@ package githubwh
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/go-playground/webhooks/v6/github"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/cosme"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
-func (c Controller) CommandHelp(ctx context.Context, payload github.IssueCommentPayload, args []string) error {
-	body := `以下のコマンドが存在します。
-* /HELP
-* /SEPARETE
-`
-	return c.githubapi.CreateIssueComment(ctx, payload.Repository.Owner.Login, payload.Repository.Name, int(payload.Issue.Number), body)
+// HelpCommandSpec is the spec of /HELP
+var HelpCommandSpec = cosme.CommandSpec{
+	Name:        "/HELP",
+	Description: catalog.Sprintf(i18n.Default, "/HELP"),
+}
+
+// CommandHelp returns the handler which shows the commands returned by listCommands
+func (c Controller) CommandHelp(listCommands func() []cosme.CommandSpec) cosme.HandlerFunc {
+	return func(ctx context.Context, payload github.IssueCommentPayload, args cosme.Args) error {
+		return c.githubapi.CreateIssueComment(ctx,
+			payload.Repository.Owner.Login, payload.Repository.Name, int(payload.Issue.Number),
+			helpMessage(c.language(payload.Repository.FullName), listCommands()))
+	}
+}
+
+func helpMessage(lang i18n.Lang, commands []cosme.CommandSpec) string {
+	var b strings.Builder
+	b.WriteString(catalog.Sprintf(lang, msgHelpHeader) + "\n")
+	for _, command := range commands {
+		fmt.Fprintf(&b, "* `%s`", command.Usage())
+		if desc := description(lang, command.Name, command.Description); desc != "" {
+			fmt.Fprintf(&b, ": %s", desc)
+		}
+		b.WriteString("\n")
+		for _, flag := range command.Flags {
+			fmt.Fprintf(&b, "    * `--%s`: %s\n", flag.Name,
+				description(lang, command.Name+" --"+flag.Name, flag.Description))
+		}
+	}
+	return b.String()
 }
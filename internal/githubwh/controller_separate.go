@@ -3,15 +3,40 @@ package githubwh
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-playground/webhooks/v6/github"
 	"golang.org/x/xerrors"
 
+	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/cosme"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
 	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 )
 
-func (c Controller) CommandSeparate(ctx context.Context, payload github.IssueCommentPayload, args []string) error {
+var (
+	// environmentAliases maps the value of --envs to the directory name.
+	// Its keys must be the same as Choices of --envs, which are validated by cosme.
+	environmentAliases = map[string]string{
+		"dev":         "development",
+		"development": "development",
+		"prod":        "production",
+		"production":  "production",
+	}
+	// SeparateCommandSpec is the spec of /SEPARATE
+	SeparateCommandSpec = cosme.CommandSpec{
+		Name:        "/SEPARATE",
+		Description: catalog.Sprintf(i18n.Default, "/SEPARATE"),
+		Flags: []cosme.FlagSpec{
+			{Name: "envs", Description: catalog.Sprintf(i18n.Default, "/SEPARATE --envs"), Default: "dev,prod",
+				Choices: []string{"dev", "development", "prod", "production"}},
+		},
+	}
+)
+
+func (c Controller) CommandSeparate(ctx context.Context, payload github.IssueCommentPayload, args cosme.Args) (err error) {
 	var (
 		supportedRepos = []string{"cloudnativedaysjp/dreamkast-infra"}
 		targetBranch   = "main"
@@ -32,15 +57,34 @@ func (c Controller) CommandSeparate(ctx context.Context, payload github.IssueCom
 	// Validate PullRequest
 	validPr, headBranchName, err := c.githubapi.CheckPrIsForInfraAndCreatedByRenovate(ctx, org, repo, prNum)
 	if err != nil {
-		return xerrors.Errorf("githubapi.CheckPrIsForInfraAndCreatedByRenovate failed: %w", err)
+		err = xerrors.Errorf("githubapi.CheckPrIsForInfraAndCreatedByRenovate failed: %w", err)
+		if githubapi.IsTransientError(err) {
+			// nothing has been changed yet, so the whole command can be retried
+			return cosme.Transient(err)
+		}
+		return err
 	}
 	if !validPr {
 		logger.Info("unsupported pullRequest")
 		return nil
 	}
+	defer func() {
+		c.audit.Record(ctx, audit.Entry{
+			Source: audit.SourceGitHub, Actor: payload.Comment.User.Login, Action: "github.separate",
+			Target: fmt.Sprintf("%s#%d", payload.Repository.FullName, prNum), Outcome: audit.OutcomeOf(err),
+			Detail: fmt.Sprintf("envs=%s", strings.Join(args.FlagList("envs"), ",")),
+		})
+	}()
 
 	// Separate PullRequest
-	prNumDev, prNumProd, err := c.service.SeparatePullRequests(ctx, org, repo, prNum, targetBranch, headBranchName)
+	var environments []string
+	for _, env := range args.FlagList("envs") {
+		environment := environmentAliases[env]
+		if !utils.Contains(environments, environment) {
+			environments = append(environments, environment)
+		}
+	}
+	prNums, err := c.service.SeparatePullRequests(ctx, org, repo, prNum, targetBranch, headBranchName, environments)
 	if err != nil {
 		return xerrors.Errorf("service.SeparatePullRequests failed: %w", err)
 	}
@@ -51,12 +95,12 @@ func (c Controller) CommandSeparate(ctx context.Context, payload github.IssueCom
 		return xerrors.Errorf("githubapi.CreateLabels failed: %w", err)
 	}
 
-	body := fmt.Sprintf(`
-separated to the following PRs
-* #%d
-* #%d
-**Please merge them instead of this PR.**
-`, prNumDev, prNumProd)
+	var prList []string
+	for _, n := range prNums {
+		prList = append(prList, fmt.Sprintf("* #%d", n))
+	}
+	body := "\n" + catalog.Sprintf(c.language(payload.Repository.FullName),
+		msgSeparated, strings.Join(prList, "\n")) + "\n"
 
 	if err := c.githubapi.CreateIssueComment(ctx, org, repo, prNum, body); err != nil {
 		return xerrors.Errorf("githubapi.CreateIssueComment failed: %w", err)
@@ -2,7 +2,10 @@ package githubwh
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	chi "github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -10,12 +13,36 @@ import (
 	"github.com/cloudnativedaysjp/seaman/cmd/seaman/config"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/gitcommand"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
 	"github.com/cloudnativedaysjp/seaman/pkg/cosme"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
 )
 
-// Run is entrypoint for runnging server for GitHub Webhook
-func Run(ctx context.Context, conf *config.Config) error {
+// Replayer re-runs recent webhook deliveries
+type Replayer interface {
+	Deliveries() []cosme.Delivery
+	Replay(ctx context.Context, deliveryId string) (bool, error)
+}
+
+type webhookHandler interface {
+	http.Handler
+	Replayer
+	RunBackground(ctx context.Context)
+	Shutdown(ctx context.Context) error
+}
+
+// Server is server for GitHub Webhook
+type Server struct {
+	bindAddr    string
+	router      http.Handler
+	handler     webhookHandler
+	gracePeriod time.Duration
+}
+
+// New initializes server for GitHub Webhook
+func New(ctx context.Context, conf *config.Config, auditor *audit.Logger) (*Server, error) {
 	logger := log.FromContext(ctx)
 
 	// initialize
@@ -30,21 +57,117 @@ func Run(ctx context.Context, conf *config.Config) error {
 	})
 	githubApiClient := githubapi.NewGitHubApiClientImpl(conf.GitHub.AccessToken)
 	gitCommandClient := gitcommand.NewGitCommandClientImpl(conf.GitHub.Username, conf.GitHub.AccessToken)
-	c := NewController(gitCommandClient, githubApiClient)
+	repoLangs := make(map[string]i18n.Lang)
+	for repo, lang := range conf.GitHubWebhook.Languages {
+		repoLangs[repo] = i18n.Lang(lang)
+	}
+	c := NewController(gitCommandClient, githubApiClient, auditor).
+		WithLanguage(i18n.Lang(conf.GitHubWebhook.Language), repoLangs)
 
 	// wrapper for GitHub Webhook Server
 	h, err := cosme.New(logger, conf.GitHubWebhook.Secret)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	queueConf := conf.GitHubWebhook.Queue
+	if queueConf.Dir != "" {
+		store, err := cosme.NewFileStore(queueConf.Dir)
+		if err != nil {
+			return nil, err
+		}
+		h.WithStore(store)
 	}
+	for command, commandConf := range conf.GitHubWebhook.Commands {
+		h.WithAuthorization(command, cosme.Authorization(commandConf))
+	}
+	h.WithFeedback(githubApiClient).
+		WithTeamMembershipChecker(githubApiClient).
+		WithDeliveryCapacity(conf.GitHubWebhook.Replay.Capacity).
+		WithWorkers(queueConf.Workers).
+		WithObserver(func(command string, duration time.Duration, err error) {
+			metrics.ObserveHandler(metrics.KindWebhookCommand, command, duration, err)
+		}).
+		// the commands are not idempotent, so they are retried only if they fail before changing
+		// anything (transient errors of each API call are retried by githubapi)
+		WithRetry(queueConf.MaxRetries,
+			time.Duration(queueConf.RetryIntervalSeconds)*time.Second,
+			cosme.IsMarkedTransient)
 
 	// routing
-	r.Mount("/webhook/github", h.
-		WithCommand("/HELP", c.CommandHelp).
-		WithCommand("/SEPARATE", c.CommandSeparate))
+	r.With(observeDelivery).Mount("/webhook/github", h.
+		WithCommandSpec(HelpCommandSpec, c.CommandHelp(h.Commands)).
+		WithCommandSpec(SeparateCommandSpec, c.CommandSeparate))
+	if token := conf.GitHubWebhook.Replay.AdminToken; token != "" {
+		r.Route("/admin/webhook", func(r chi.Router) {
+			r.Use(requireBearerToken(token))
+			r.Get("/deliveries", listDeliveries(h))
+			r.Post("/deliveries/{deliveryId}/replay", replayDelivery(h, auditor))
+		})
+	}
+
+	return &Server{conf.GitHubWebhook.BindAddr, r, h,
+		time.Duration(conf.ShutdownGracePeriodSeconds) * time.Second}, nil
+}
 
-	if err := http.ListenAndServe(conf.GitHubWebhook.BindAddr, r); err != nil {
+// Replayer returns Replayer for recent webhook deliveries
+func (s *Server) Replayer() Replayer {
+	return s.handler
+}
+
+// Run is entrypoint for runnging server for GitHub Webhook.
+// When ctx is done, it stops accepting requests and waits for in-flight
+// requests and queued commands up to the grace period.
+func (s *Server) Run(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	// the queue is stopped only after the grace period
+	queueCtx, stopQueue := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopQueue()
+	queueStopped := make(chan struct{})
+	go func() {
+		s.handler.RunBackground(queueCtx)
+		close(queueStopped)
+	}()
+
+	server := &http.Server{Addr: s.bindAddr, Handler: s.router}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down webhook server")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.gracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Warn(fmt.Sprintf("failed to wait for in-flight requests: %v", err))
+	}
+	if err := s.handler.Shutdown(shutdownCtx); err != nil {
+		logger.Warn(fmt.Sprintf("queued commands are left for the next launch: %v", err))
+	}
+	stopQueue()
+	select {
+	case <-queueStopped:
+	case <-shutdownCtx.Done():
+		logger.Warn("in-flight commands are interrupted")
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	return nil
 }
+
+// observeDelivery records the response code for each delivery
+func observeDelivery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		metrics.ObserveWebhookDelivery(r.Header.Get("X-GitHub-Event"), code)
+	})
+}
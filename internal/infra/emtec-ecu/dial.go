@@ -0,0 +1,145 @@
+package emtec_ecu
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+
+	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
+)
+
+type DialOptions struct {
+	TLS TLSOptions
+	// MaxAttempts is the max number of attempts (including the first one)
+	// of idempotent RPCs retried on UNAVAILABLE
+	MaxAttempts int
+	// KeepaliveTime is the interval of keepalive pings (disabled if 0).
+	// emtec-ecu must permit it by its keepalive enforcement policy.
+	KeepaliveTime time.Duration
+}
+
+type TLSOptions struct {
+	Enabled bool
+	// CAFile is the CA certificate for verifying the server (system roots if empty)
+	CAFile string
+	// CertFile and KeyFile are the client certificate for mTLS (optional)
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the server name used for verification
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Dial creates a connection to emtec-ecu. The connection is established lazily,
+// so this returns an error only if the options are invalid.
+func Dial(target string, opts DialOptions) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig, err := retryServiceConfig(opts.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor("emtec-ecu")),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if opts.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    opts.KeepaliveTime,
+			Timeout: opts.KeepaliveTime,
+		}))
+	}
+	return grpc.NewClient(target, dialOpts...)
+}
+
+func transportCredentials(opts TLSOptions) (credentials.TransportCredentials, error) {
+	if !opts.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+	conf := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec // explicitly configured
+	}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, xerrors.Errorf("no valid certificate in CA file %s", opts.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(conf), nil
+}
+
+// retryServiceConfig returns the service config which retries idempotent RPCs.
+// MoveSceneToNext is not retried because retrying it may skip a scene.
+func retryServiceConfig(maxAttempts int) (string, error) {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []name      `json:"name"`
+		RetryPolicy retryPolicy `json:"retryPolicy"`
+	}
+	if maxAttempts < 2 {
+		return `{}`, nil
+	}
+	scene := pb.SceneService_ServiceDesc.ServiceName
+	track := pb.TrackService_ServiceDesc.ServiceName
+	data, err := json.Marshal(map[string]any{
+		"methodConfig": []methodConfig{{
+			Name: []name{
+				{scene, "ListScene"},
+				{track, "GetTrack"},
+				{track, "ListTrack"},
+				{track, "EnableAutomation"},
+				{track, "DisableAutomation"},
+			},
+			RetryPolicy: retryPolicy{
+				MaxAttempts:          maxAttempts,
+				InitialBackoff:       "0.5s",
+				MaxBackoff:           "5s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
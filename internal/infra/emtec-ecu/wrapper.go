@@ -2,8 +2,10 @@ package emtec_ecu
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
@@ -12,10 +14,72 @@ import (
 type CndWrapper struct {
 	Scene pb.SceneServiceClient
 	Track pb.TrackServiceClient
+
+	conn    *grpc.ClientConn
+	dialErr error
+	timeout time.Duration
 }
 
 func NewCndWrapper(scene pb.SceneServiceClient, track pb.TrackServiceClient) *CndWrapper {
-	return &CndWrapper{scene, track}
+	return &CndWrapper{Scene: scene, Track: track}
+}
+
+// NewCndWrapperFromConn returns CndWrapper using conn.
+// If dialErr is not nil, CndWrapper is always unavailable.
+func NewCndWrapperFromConn(conn *grpc.ClientConn, dialErr error) *CndWrapper {
+	w := &CndWrapper{conn: conn, dialErr: dialErr}
+	if conn != nil {
+		w.Scene = pb.NewSceneServiceClient(conn)
+		w.Track = pb.NewTrackServiceClient(conn)
+	}
+	return w
+}
+
+// WithTimeout sets the deadline of each call (no deadline if 0)
+func (w *CndWrapper) WithTimeout(timeout time.Duration) *CndWrapper {
+	w.timeout = timeout
+	return w
+}
+
+// Available returns false and the reason if emtec-ecu seems to be down.
+// Idle or connecting connections are regarded as available because
+// the connection is established lazily.
+func (w CndWrapper) Available() (bool, string) {
+	if w.dialErr != nil {
+		return false, w.dialErr.Error()
+	}
+	if w.conn == nil {
+		return true, ""
+	}
+	switch state := w.conn.GetState(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false, state.String()
+	default:
+		return true, state.String()
+	}
+}
+
+// Ready returns true if the connection is ready.
+// Idle connections start connecting by calling it.
+func (w CndWrapper) Ready() (bool, string) {
+	if w.dialErr != nil {
+		return false, w.dialErr.Error()
+	}
+	if w.conn == nil {
+		return true, ""
+	}
+	state := w.conn.GetState()
+	if state == connectivity.Idle {
+		w.conn.Connect()
+	}
+	return state == connectivity.Ready, state.String()
+}
+
+func (w CndWrapper) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, w.timeout)
 }
 
 //
@@ -25,10 +89,14 @@ func NewCndWrapper(scene pb.SceneServiceClient, track pb.TrackServiceClient) *Cn
 var _ pb.SceneServiceClient = (*CndWrapper)(nil)
 
 func (w CndWrapper) ListScene(ctx context.Context, in *pb.ListSceneRequest, opts ...grpc.CallOption) (*pb.ListSceneResponse, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Scene.ListScene(ctx, in, opts...)
 }
 
 func (w CndWrapper) MoveSceneToNext(ctx context.Context, in *pb.MoveSceneToNextRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Scene.MoveSceneToNext(ctx, in, opts...)
 }
 
@@ -39,17 +107,25 @@ func (w CndWrapper) MoveSceneToNext(ctx context.Context, in *pb.MoveSceneToNextR
 var _ pb.TrackServiceClient = (*CndWrapper)(nil)
 
 func (w CndWrapper) GetTrack(ctx context.Context, in *pb.GetTrackRequest, opts ...grpc.CallOption) (*pb.Track, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Track.GetTrack(ctx, in, opts...)
 }
 
 func (w CndWrapper) ListTrack(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*pb.ListTrackResponse, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Track.ListTrack(ctx, in, opts...)
 }
 
 func (w CndWrapper) EnableAutomation(ctx context.Context, in *pb.SwitchAutomationRequest, opts ...grpc.CallOption) (*pb.Track, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Track.EnableAutomation(ctx, in, opts...)
 }
 
 func (w CndWrapper) DisableAutomation(ctx context.Context, in *pb.SwitchAutomationRequest, opts ...grpc.CallOption) (*pb.Track, error) {
+	ctx, cancel := w.withTimeout(ctx)
+	defer cancel()
 	return w.Track.DisableAutomation(ctx, in, opts...)
 }
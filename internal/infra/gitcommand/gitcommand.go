@@ -11,8 +11,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
+
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
+	"github.com/cloudnativedaysjp/seaman/internal/tracing"
 )
 
 type GitCommandClient interface {
@@ -40,6 +47,8 @@ var (
 	baseURL = `https://%s:%s@github.com`
 )
 
+var tracer = otel.Tracer("github.com/cloudnativedaysjp/seaman/internal/infra/gitcommand")
+
 type CloneOpt struct {
 	Branch string
 	Depth  int
@@ -62,7 +71,10 @@ func (g *GitCommandClientImpl) Clone(ctx context.Context, org, repo string, opt
 	)
 	cmd := exec.CommandContext(ctx, commands[0], commands[1:]...)
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	start := time.Now()
+	_, err := output(ctx, cmd)
+	metrics.ObserveExternal("git", "clone", time.Since(start), err)
+	if err != nil {
 		return "", xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	return downloadDir, nil
@@ -71,25 +83,25 @@ func (g *GitCommandClientImpl) Clone(ctx context.Context, org, repo string, opt
 func (g *GitCommandClientImpl) CommitAll(ctx context.Context, dirPath, commitMsg string) error {
 	cmd := exec.CommandContext(ctx, "git", "config", "user.name", g.user)
 	cmd.Dir = dirPath
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("message: %w", err)
 	}
 	cmd = exec.CommandContext(ctx, "git", "config", "user.email", g.email)
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	cmd = exec.CommandContext(ctx, "git", "add", "-A")
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	cmd = exec.CommandContext(ctx, "git", "commit", "--allow-empty", "-m", commitMsg)
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	return nil
@@ -99,26 +111,26 @@ func (g *GitCommandClientImpl) CommitAllAmend(ctx context.Context, dirPath strin
 	cmd := exec.CommandContext(ctx, "git", "config", "user.name", g.user)
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	cmd = exec.CommandContext(ctx, "git", "config", "user.email", g.email)
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	cmd = exec.CommandContext(ctx, "git", "add", "-A")
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	cmd = exec.CommandContext(ctx,
 		"git", "commit", "--amend", "--no-edit", "--author", fmt.Sprintf("%s <%s>", g.user, g.email))
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	return nil
@@ -132,7 +144,10 @@ func (g *GitCommandClientImpl) Push(ctx context.Context, dirPath string) error {
 	cmd := exec.CommandContext(ctx, "git", "push", "origin", "HEAD")
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	start := time.Now()
+	_, err := output(ctx, cmd)
+	metrics.ObserveExternal("git", "push", time.Since(start), err)
+	if err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	return nil
@@ -149,7 +164,7 @@ func (g *GitCommandClientImpl) Restore(ctx context.Context, dirPath, sourceBranc
 	cmd := exec.CommandContext(ctx, "git", "restore", "-s", "origin/"+sourceBranch, strings.Join(filePaths, " "))
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 
@@ -160,8 +175,18 @@ func (g *GitCommandClientImpl) SwitchNewBranch(ctx context.Context, dirPath, bra
 	cmd := exec.CommandContext(ctx, "git", "switch", "-c", branch)
 	cmd.Dir = dirPath
 	cmd.Stderr = &bytes.Buffer{}
-	if _, err := cmd.Output(); err != nil {
+	if _, err := output(ctx, cmd); err != nil {
 		return xerrors.Errorf("%v: %w", cmd.Stderr, err)
 	}
 	return nil
 }
+
+// output runs cmd in the span named after the git subcommand.
+// Arguments are not recorded because the remote URL contains the token.
+func output(ctx context.Context, cmd *exec.Cmd) (_ []byte, err error) {
+	_, span := tracer.Start(ctx, "git "+cmd.Args[1], trace.WithAttributes(
+		attribute.String("git.dir", cmd.Dir),
+	))
+	defer func() { tracing.End(span, err) }()
+	return cmd.Output()
+}
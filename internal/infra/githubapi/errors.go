@@ -0,0 +1,58 @@
+package githubapi
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// githubv4 returns the error like `non-200 OK status code: 502 Bad Gateway body: "..."`
+var reNon200StatusCode = regexp.MustCompile(`non-200 OK status code: (429|5\d\d) `)
+
+// IsTransientError reports whether err is caused by a temporary failure of
+// GitHub (rate limit, 5xx or network timeout), so that the request may succeed on retry.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return reNon200StatusCode.MatchString(err.Error())
+}
+
+// GraphQL returns the error like `Could not resolve to a Repository with the name 'org/repo'.`
+// if the object specified by the query does not exist
+var reCouldNotResolve = regexp.MustCompile(`^Could not resolve to an? `)
+
+// NotFoundError is returned if the object specified by the request does not exist
+type NotFoundError struct {
+	// Detail describes the missing object. It contains only names given by the caller,
+	// so it is safe to show to users.
+	Detail string
+	Err    error
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Err == nil {
+		return e.Detail
+	}
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError converts the GraphQL error of a missing object into NotFoundError
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := err.Error(); reCouldNotResolve.MatchString(msg) {
+		return &NotFoundError{Detail: strings.TrimSuffix(msg, "."), Err: err}
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package githubapi
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func Test_classifyError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		err    error
+		detail string
+	}{
+		{
+			name:   "missing repository",
+			err:    errors.New("Could not resolve to a Repository with the name 'org/repo'."),
+			detail: "Could not resolve to a Repository with the name 'org/repo'",
+		},
+		{
+			name:   "missing object with an",
+			err:    errors.New("Could not resolve to an Organization with the login of 'org'."),
+			detail: "Could not resolve to an Organization with the login of 'org'",
+		},
+		{
+			name: "other error",
+			err:  errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := xerrors.Errorf("%w", classifyError(tt.err))
+			var nf *NotFoundError
+			if ok := errors.As(err, &nf); ok != (tt.detail != "") {
+				t.Fatalf("errors.As(NotFoundError) = %v", ok)
+			}
+			if nf != nil && nf.Detail != tt.detail {
+				t.Errorf("Detail = %q, want %q", nf.Detail, tt.detail)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("cause is lost: %v", err)
+			}
+		})
+	}
+}
@@ -5,23 +5,28 @@ package githubapi
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
 	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 	"github.com/shurcooL/githubv4"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 )
 
 type GitHubApiClient interface {
+	AddReaction(ctx context.Context, subjectId, content string) error
 	CheckPrIsForInfraAndCreatedByRenovate(ctx context.Context, org, repo string, prNum int) (bool, string, error)
 	CreateIssueComment(ctx context.Context, org, repo string, prNum int, body string) error
 	CreateLabels(ctx context.Context, org, repo string, prNum int, labels []string) error
 	CreatePullRequest(ctx context.Context, org, repo, headBranch, baseBranch, title, body string) (prNum int, err error)
 	DeleteBranch(ctx context.Context, org, repo, headBranch string) error
 	GetPullRequestTitleAndChangedFilepaths(ctx context.Context, org, repo string, prNum int) (string, []string, error)
-	HealthCheck() error
+	HealthCheck(ctx context.Context) error
+	IsTeamMember(ctx context.Context, org, teamSlug, user string) (bool, error)
 	UpdatePullRequestBody(ctx context.Context, org, repo string, prNum int, body string) error
 }
 
@@ -40,9 +45,29 @@ func NewGitHubApiClientImpl(token string) GitHubApiClient {
 // Exposed methods
 //
 
+// AddReaction adds the reaction (e.g. "EYES", "ROCKET") to the subject specified by node ID
+func (g *GitHubApiClientImpl) AddReaction(ctx context.Context, subjectId, content string) error {
+	client := g.newClient(ctx, "AddReaction")
+
+	var mutationAddReaction struct {
+		AddReaction struct {
+			Reaction struct {
+				Content githubv4.ReactionContent
+			}
+		} `graphql:"addReaction(input:$input)"`
+	}
+	if err := client.Mutate(ctx, &mutationAddReaction, githubv4.AddReactionInput{
+		SubjectID: githubv4.ID(subjectId),
+		Content:   githubv4.ReactionContent(content),
+	}, nil); err != nil {
+		return xerrors.Errorf("%w", classifyError(err))
+	}
+	return nil
+}
+
 func (g *GitHubApiClientImpl) CheckPrIsForInfraAndCreatedByRenovate(ctx context.Context, org, repo string, prNum int) (bool, string, error) {
 	logger := log.FromContext(ctx)
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "CheckPrIsForInfraAndCreatedByRenovate")
 	expectedNumOfUpdatedFiles := 2
 	labelLimit := 10
 
@@ -76,7 +101,7 @@ func (g *GitHubApiClientImpl) CheckPrIsForInfraAndCreatedByRenovate(ctx context.
 		"labelsFirst":     githubv4.Int(labelLimit),
 	}
 	if err := client.Query(ctx, &query, queryVars); err != nil {
-		return false, "", xerrors.Errorf("%w", err)
+		return false, "", xerrors.Errorf("%w", classifyError(err))
 	}
 	// if changeFiles == `expectedNumOfUpdatedFiles`
 	if int(query.Repository.PullRequest.ChangedFiles) != expectedNumOfUpdatedFiles {
@@ -103,7 +128,7 @@ func (g *GitHubApiClientImpl) CheckPrIsForInfraAndCreatedByRenovate(ctx context.
 }
 
 func (g *GitHubApiClientImpl) CreateIssueComment(ctx context.Context, org, repo string, prNum int, body string) error {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "CreateIssueComment")
 
 	prId, err := g.getPullRequestId(ctx, org, repo, prNum)
 	if err != nil {
@@ -121,13 +146,13 @@ func (g *GitHubApiClientImpl) CreateIssueComment(ctx context.Context, org, repo
 		SubjectID: prId,
 		Body:      githubv4.String(body),
 	}, nil); err != nil {
-		return xerrors.Errorf("%w", err)
+		return xerrors.Errorf("%w", classifyError(err))
 	}
 	return nil
 }
 
 func (g *GitHubApiClientImpl) CreateLabels(ctx context.Context, org, repo string, prNum int, labels []string) error {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "CreateLabels")
 
 	prId, err := g.getPullRequestId(ctx, org, repo, prNum)
 	if err != nil {
@@ -153,13 +178,13 @@ func (g *GitHubApiClientImpl) CreateLabels(ctx context.Context, org, repo string
 		PullRequestID: prId,
 		LabelIDs:      &labelIds,
 	}, nil); err != nil {
-		return xerrors.Errorf("CreateLabels failed: %w", err)
+		return xerrors.Errorf("CreateLabels failed: %w", classifyError(err))
 	}
 	return nil
 }
 
 func (g *GitHubApiClientImpl) CreatePullRequest(ctx context.Context, org, repo, headBranch, baseBranch, title, body string) (prNum int, err error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "CreatePullRequest")
 
 	repoId, err := g.getRepositoryId(ctx, org, repo)
 	if err != nil {
@@ -180,14 +205,14 @@ func (g *GitHubApiClientImpl) CreatePullRequest(ctx context.Context, org, repo,
 		Title:        githubv4.String(title),
 		Body:         githubv4.NewString(githubv4.String(body)),
 	}, nil); err != nil {
-		return 0, err
+		return 0, classifyError(err)
 	}
 
 	return mutationCreatePR.CreatePullRequest.PullRequest.Number, nil
 }
 
 func (g *GitHubApiClientImpl) DeleteBranch(ctx context.Context, org, repo, headBranch string) error {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "DeleteBranch")
 
 	id, err := g.getBranchId(ctx, org, repo, headBranch)
 	if err != nil {
@@ -202,13 +227,13 @@ func (g *GitHubApiClientImpl) DeleteBranch(ctx context.Context, org, repo, headB
 	if err := client.Mutate(ctx, &mutationDeleteBranch, githubv4.DeleteRefInput{
 		RefID: id,
 	}, nil); err != nil {
-		return xerrors.Errorf("%w", err)
+		return xerrors.Errorf("%w", classifyError(err))
 	}
 	return nil
 }
 
 func (g *GitHubApiClientImpl) GetPullRequestTitleAndChangedFilepaths(ctx context.Context, org, repo string, prNum int) (string, []string, error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "GetPullRequestTitleAndChangedFilepaths")
 	pageLimit := 10
 
 	var query struct {
@@ -240,7 +265,7 @@ func (g *GitHubApiClientImpl) GetPullRequestTitleAndChangedFilepaths(ctx context
 	changedFiles := []string{}
 	for i := 0; i*pageLimit < changedFilesNum; i++ {
 		if err := client.Query(ctx, &query, queryVars); err != nil {
-			return "", nil, xerrors.Errorf("%w", err)
+			return "", nil, xerrors.Errorf("%w", classifyError(err))
 		}
 		for _, edge := range query.Repository.PullRequest.Files.Edges {
 			changedFiles = append(changedFiles, string(edge.Node.Path))
@@ -255,22 +280,58 @@ func (g *GitHubApiClientImpl) GetPullRequestTitleAndChangedFilepaths(ctx context
 	return title, changedFiles, nil
 }
 
-func (g *GitHubApiClientImpl) HealthCheck() error {
-	ctx := context.Background()
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+// HealthCheck queries the viewer to confirm the token is valid
+func (g *GitHubApiClientImpl) HealthCheck(ctx context.Context) error {
+	client := g.newClient(ctx, "HealthCheck")
 	var q struct {
 		Viewer struct {
 			Login githubv4.String
 		}
 	}
 	if err := client.Query(ctx, &q, nil); err != nil {
-		return xerrors.Errorf("%w", err)
+		return xerrors.Errorf("%w", classifyError(err))
 	}
 	return nil
 }
 
+// IsTeamMember requires the token to have read:org scope
+func (g *GitHubApiClientImpl) IsTeamMember(ctx context.Context, org, teamSlug, user string) (bool, error) {
+	client := g.newClient(ctx, "IsTeamMember")
+	memberLimit := 100
+
+	var query struct {
+		Organization struct {
+			Team *struct {
+				Members struct {
+					Nodes []struct {
+						Login githubv4.String
+					}
+				} `graphql:"members(query:$user,first:$first)"`
+			} `graphql:"team(slug:$teamSlug)"`
+		} `graphql:"organization(login:$org)"`
+	}
+	if err := client.Query(ctx, &query, map[string]any{
+		"org":      githubv4.String(org),
+		"teamSlug": githubv4.String(teamSlug),
+		"user":     githubv4.String(user),
+		"first":    githubv4.Int(memberLimit),
+	}); err != nil {
+		return false, xerrors.Errorf("%w", classifyError(err))
+	}
+	if query.Organization.Team == nil {
+		return false, xerrors.Errorf("no such team: %s/%s", org, teamSlug)
+	}
+	// `query` matches partially, so confirm exact match
+	for _, node := range query.Organization.Team.Members.Nodes {
+		if strings.EqualFold(string(node.Login), user) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (g *GitHubApiClientImpl) UpdatePullRequestBody(ctx context.Context, org, repo string, prNum int, body string) error {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "UpdatePullRequestBody")
 
 	prId, err := g.getPullRequestId(ctx, org, repo, prNum)
 	if err != nil {
@@ -288,7 +349,7 @@ func (g *GitHubApiClientImpl) UpdatePullRequestBody(ctx context.Context, org, re
 		PullRequestID: prId,
 		Body:          githubv4.NewString(githubv4.String(body)),
 	}, nil); err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	return nil
@@ -298,8 +359,24 @@ func (g *GitHubApiClientImpl) UpdatePullRequestBody(ctx context.Context, org, re
 // Unexposed methods
 //
 
+// newClient returns GitHub GraphQL client whose requests are recorded
+// as the operation in metrics and spans, and retried on transient errors
+func (g *GitHubApiClientImpl) newClient(ctx context.Context, operation string) *githubv4.Client {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+		Transport: &retryTransport{
+			base: otelhttp.NewTransport(
+				metrics.InstrumentRoundTripper("github", metrics.Operation(operation), nil),
+				otelhttp.WithSpanNameFormatter(func(string, *http.Request) string { return "github " + operation }),
+			),
+			maxRetries: maxRetries,
+			interval:   retryInterval,
+		},
+	})
+	return githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+}
+
 func (g *GitHubApiClientImpl) getBranchId(ctx context.Context, org, repo, branch string) (githubv4.ID, error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "getBranchId")
 	var queryGetBranchID struct {
 		Repository struct {
 			Ref struct {
@@ -312,13 +389,13 @@ func (g *GitHubApiClientImpl) getBranchId(ctx context.Context, org, repo, branch
 		"repositoryName":  githubv4.String(repo),
 		"qualifiedName":   githubv4.String(branch),
 	}); err != nil {
-		return nil, xerrors.Errorf("%w", err)
+		return nil, xerrors.Errorf("%w", classifyError(err))
 	}
 	return queryGetBranchID.Repository.Ref.ID, nil
 }
 
 func (g *GitHubApiClientImpl) getLabelId(ctx context.Context, org, repo, label string) (githubv4.ID, error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "getLabelId")
 	var queryGetLabel struct {
 		Repository struct {
 			Label struct {
@@ -331,16 +408,16 @@ func (g *GitHubApiClientImpl) getLabelId(ctx context.Context, org, repo, label s
 		"repositoryName":  githubv4.String(repo),
 		"labelName":       githubv4.String(label),
 	}); err != nil {
-		return nil, xerrors.Errorf("%w", err)
+		return nil, xerrors.Errorf("%w", classifyError(err))
 	}
 	if queryGetLabel.Repository.Label.ID == nil {
-		return nil, xerrors.Errorf("no such label: %s", label)
+		return nil, xerrors.Errorf("%w", &NotFoundError{Detail: fmt.Sprintf("label not found: %s", label)})
 	}
 	return queryGetLabel.Repository.Label.ID, nil
 }
 
 func (g *GitHubApiClientImpl) getPullRequestId(ctx context.Context, org, repo string, prNum int) (githubv4.ID, error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "getPullRequestId")
 	var queryGetPullRequest struct {
 		Repository struct {
 			PullRequest struct {
@@ -353,13 +430,13 @@ func (g *GitHubApiClientImpl) getPullRequestId(ctx context.Context, org, repo st
 		"repositoryName":    githubv4.String(repo),
 		"pullRequestNumber": githubv4.Int(prNum),
 	}); err != nil {
-		return 0, xerrors.Errorf("%w", err)
+		return 0, xerrors.Errorf("%w", classifyError(err))
 	}
 	return queryGetPullRequest.Repository.PullRequest.ID, nil
 }
 
 func (g *GitHubApiClientImpl) getRepositoryId(ctx context.Context, org, repo string) (githubv4.ID, error) {
-	client := githubv4.NewClient(oauth2.NewClient(ctx, g.tokenSource))
+	client := g.newClient(ctx, "getRepositoryId")
 	var queryGetRepository struct {
 		Repository struct {
 			ID githubv4.String
@@ -369,7 +446,7 @@ func (g *GitHubApiClientImpl) getRepositoryId(ctx context.Context, org, repo str
 		"repositoryOwner": githubv4.String(org),
 		"repositoryName":  githubv4.String(repo),
 	}); err != nil {
-		return 0, xerrors.Errorf("%w", err)
+		return 0, xerrors.Errorf("%w", classifyError(err))
 	}
 	return queryGetRepository.Repository.ID, nil
 }
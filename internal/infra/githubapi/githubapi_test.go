@@ -12,7 +12,7 @@ func Test_GitHubApiDriver(t *testing.T) {
 	c := NewGitHubApiClient(os.Getenv("GITHUB_TOKEN"))
 
 	t.Run(`HealthCheck`, func(t *testing.T) {
-		err := c.HealthCheck()
+		err := c.HealthCheck(context.Background())
 		if err != nil {
 			t.Fatalf("error: %s", err)
 		}
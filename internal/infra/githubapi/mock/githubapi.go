@@ -34,6 +34,20 @@ func (m *MockGitHubApiClient) EXPECT() *MockGitHubApiClientMockRecorder {
 	return m.recorder
 }
 
+// AddReaction mocks base method.
+func (m *MockGitHubApiClient) AddReaction(ctx context.Context, subjectId, content string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, subjectId, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockGitHubApiClientMockRecorder) AddReaction(ctx, subjectId, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockGitHubApiClient)(nil).AddReaction), ctx, subjectId, content)
+}
+
 // CheckPrIsForInfraAndCreatedByRenovate mocks base method.
 func (m *MockGitHubApiClient) CheckPrIsForInfraAndCreatedByRenovate(ctx context.Context, org, repo string, prNum int) (bool, string, error) {
 	m.ctrl.T.Helper()
@@ -124,17 +138,32 @@ func (mr *MockGitHubApiClientMockRecorder) GetPullRequestTitleAndChangedFilepath
 }
 
 // HealthCheck mocks base method.
-func (m *MockGitHubApiClient) HealthCheck() error {
+func (m *MockGitHubApiClient) HealthCheck(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HealthCheck")
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // HealthCheck indicates an expected call of HealthCheck.
-func (mr *MockGitHubApiClientMockRecorder) HealthCheck() *gomock.Call {
+func (mr *MockGitHubApiClientMockRecorder) HealthCheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockGitHubApiClient)(nil).HealthCheck), ctx)
+}
+
+// IsTeamMember mocks base method.
+func (m *MockGitHubApiClient) IsTeamMember(ctx context.Context, org, teamSlug, user string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTeamMember", ctx, org, teamSlug, user)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTeamMember indicates an expected call of IsTeamMember.
+func (mr *MockGitHubApiClientMockRecorder) IsTeamMember(ctx, org, teamSlug, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockGitHubApiClient)(nil).HealthCheck))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTeamMember", reflect.TypeOf((*MockGitHubApiClient)(nil).IsTeamMember), ctx, org, teamSlug, user)
 }
 
 // UpdatePullRequestBody mocks base method.
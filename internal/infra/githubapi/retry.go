@@ -0,0 +1,61 @@
+package githubapi
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxRetries is the number of retries of a request failed with a transient error
+	maxRetries = 3
+	// retryInterval is the interval before the first retry, which is doubled on each retry
+	retryInterval = time.Second
+)
+
+// retryTransport retries a request failed with a transient error (rate limit, 5xx
+// or network timeout). Each API call is retried by itself, because the callers chain
+// non-idempotent calls (e.g. pushing branches and creating PullRequests), which
+// cannot be retried as a whole after some of them have succeeded.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	interval   time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !isTransientResponse(resp, err) ||
+			(req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.interval << attempt):
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func isTransientResponse(resp *http.Response, err error) bool {
+	if err != nil {
+		var ne net.Error
+		return errors.As(err, &ne) && ne.Timeout()
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,76 @@
+package githubapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_retryTransport(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+		requests int
+	}{
+		{
+			name:     "success",
+			statuses: []int{http.StatusOK},
+			want:     http.StatusOK,
+			requests: 1,
+		},
+		{
+			name:     "recovered from 502",
+			statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			want:     http.StatusOK,
+			requests: 3,
+		},
+		{
+			name:     "retries exhausted",
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			want:     http.StatusBadGateway,
+			requests: 3,
+		},
+		{
+			name:     "not transient",
+			statuses: []int{http.StatusUnauthorized, http.StatusOK},
+			want:     http.StatusUnauthorized,
+			requests: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"query":"{viewer{login}}"}` {
+					t.Errorf("body = %q", body)
+				}
+				w.WriteHeader(tt.statuses[requests])
+				requests++
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &retryTransport{
+				base: http.DefaultTransport, maxRetries: 2, interval: time.Millisecond,
+			}}
+			resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"{viewer{login}}"}`))
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if requests != tt.requests {
+				t.Errorf("requests = %d, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
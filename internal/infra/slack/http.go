@@ -0,0 +1,22 @@
+package slack
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
+)
+
+// NewHTTPClient returns the HTTP client for Slack API whose requests are
+// recorded as the API method in metrics and spans
+func NewHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(
+			metrics.InstrumentRoundTripper("slack", metrics.OperationFromPath, nil),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "slack " + metrics.OperationFromPath(r)
+			}),
+		),
+	}
+}
@@ -35,6 +35,34 @@ func (m *MockSlackClient) EXPECT() *MockSlackClientMockRecorder {
 	return m.recorder
 }
 
+// OpenView mocks base method.
+func (m *MockSlackClient) OpenView(ctx context.Context, triggerId string, view slack.ModalViewRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenView", ctx, triggerId, view)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OpenView indicates an expected call of OpenView.
+func (mr *MockSlackClientMockRecorder) OpenView(ctx, triggerId, view any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenView", reflect.TypeOf((*MockSlackClient)(nil).OpenView), ctx, triggerId, view)
+}
+
+// PostEphemeral mocks base method.
+func (m *MockSlackClient) PostEphemeral(ctx context.Context, channel, threadTs, user string, msg slack.Msg) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostEphemeral", ctx, channel, threadTs, user, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostEphemeral indicates an expected call of PostEphemeral.
+func (mr *MockSlackClientMockRecorder) PostEphemeral(ctx, channel, threadTs, user, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostEphemeral", reflect.TypeOf((*MockSlackClient)(nil).PostEphemeral), ctx, channel, threadTs, user, msg)
+}
+
 // PostMessage mocks base method.
 func (m *MockSlackClient) PostMessage(ctx context.Context, channel string, msg slack.Msg) error {
 	m.ctrl.T.Helper()
@@ -49,6 +77,21 @@ func (mr *MockSlackClientMockRecorder) PostMessage(ctx, channel, msg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockSlackClient)(nil).PostMessage), ctx, channel, msg)
 }
 
+// PostMessageAndGetTs mocks base method.
+func (m *MockSlackClient) PostMessageAndGetTs(ctx context.Context, channel string, msg slack.Msg) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostMessageAndGetTs", ctx, channel, msg)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostMessageAndGetTs indicates an expected call of PostMessageAndGetTs.
+func (mr *MockSlackClientMockRecorder) PostMessageAndGetTs(ctx, channel, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessageAndGetTs", reflect.TypeOf((*MockSlackClient)(nil).PostMessageAndGetTs), ctx, channel, msg)
+}
+
 // PostMessageToThread mocks base method.
 func (m *MockSlackClient) PostMessageToThread(ctx context.Context, channel, ts string, msg slack.Msg) error {
 	m.ctrl.T.Helper()
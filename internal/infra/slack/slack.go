@@ -11,8 +11,11 @@ import (
 
 type SlackClient interface {
 	PostMessage(ctx context.Context, channel string, msg slack.Msg) error
+	PostMessageAndGetTs(ctx context.Context, channel string, msg slack.Msg) (ts string, err error)
 	PostMessageToThread(ctx context.Context, channel, ts string, msg slack.Msg) error
 	UpdateMessage(ctx context.Context, channel, ts string, msg slack.Msg) error
+	PostEphemeral(ctx context.Context, channel, threadTs, user string, msg slack.Msg) error
+	OpenView(ctx context.Context, triggerId string, view slack.ModalViewRequest) error
 }
 
 type SlackClientImpl struct {
@@ -41,6 +44,18 @@ func (s *SlackClientImpl) PostMessage(ctx context.Context, channel string, msg s
 	return nil
 }
 
+func (s *SlackClientImpl) PostMessageAndGetTs(ctx context.Context, channel string, msg slack.Msg) (string, error) {
+	_, ts, err := s.client.PostMessageContext(ctx, channel,
+		slack.MsgOptionText(msg.Text, false),
+		slack.MsgOptionAttachments(msg.Attachments...),
+		slack.MsgOptionBlocks(msg.Blocks.BlockSet...),
+	)
+	if err != nil {
+		return "", xerrors.Errorf("%w", err)
+	}
+	return ts, nil
+}
+
 func (s *SlackClientImpl) PostMessageToThread(ctx context.Context, channel, messageTs string, msg slack.Msg) error {
 	_, _, err := s.client.PostMessageContext(ctx, channel,
 		slack.MsgOptionText(msg.Text, false),
@@ -66,3 +81,26 @@ func (s *SlackClientImpl) UpdateMessage(ctx context.Context, channel, ts string,
 	}
 	return nil
 }
+
+// PostEphemeral posts msg visible only to user. If threadTs is not empty, it is posted to the thread.
+func (s *SlackClientImpl) PostEphemeral(ctx context.Context, channel, threadTs, user string, msg slack.Msg) error {
+	options := []slack.MsgOption{
+		slack.MsgOptionText(msg.Text, false),
+		slack.MsgOptionAttachments(msg.Attachments...),
+		slack.MsgOptionBlocks(msg.Blocks.BlockSet...),
+	}
+	if threadTs != "" {
+		options = append(options, slack.MsgOptionTS(threadTs))
+	}
+	if _, err := s.client.PostEphemeralContext(ctx, channel, user, options...); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *SlackClientImpl) OpenView(ctx context.Context, triggerId string, view slack.ModalViewRequest) error {
+	if _, err := s.client.OpenViewContext(ctx, triggerId, view); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+)
+
+const namespace = "seaman"
+
+// Kind of handlers
+const (
+	KindCommand        = "command"
+	KindWebhookCommand = "webhook_command"
+)
+
+// Outcome of handlers and external requests
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+var (
+	handledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handled_total",
+		Help:      "Number of handled Slack commands, Slack interactions and GitHub comment commands.",
+	}, []string{"kind", "name", "outcome"})
+	handleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handle_duration_seconds",
+		Help:      "Latency of handling Slack commands, Slack interactions and GitHub comment commands.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"kind", "name"})
+
+	webhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Number of received GitHub Webhook deliveries.",
+	}, []string{"event", "code"})
+
+	externalRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "external_requests_total",
+		Help:      "Number of requests to external services (Slack, GitHub, git and EMTEC-ECU).",
+	}, []string{"service", "operation", "outcome"})
+	externalRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "external_request_duration_seconds",
+		Help:      "Latency of requests to external services (Slack, GitHub, git and EMTEC-ECU).",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "operation"})
+)
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// ObserveHandler records the result of the handler.
+// kind is KindCommand, KindWebhookCommand or the type of Slack interaction.
+func ObserveHandler(kind, name string, duration time.Duration, err error) {
+	handledTotal.WithLabelValues(kind, name, outcome(err)).Inc()
+	handleDuration.WithLabelValues(kind, name).Observe(duration.Seconds())
+}
+
+// ObserveWebhookDelivery records the response code for the delivery of the event
+func ObserveWebhookDelivery(event string, code int) {
+	webhookDeliveriesTotal.WithLabelValues(event, strconv.Itoa(code)).Inc()
+}
+
+// ObserveExternal records the result of the request to the external service
+func ObserveExternal(service, operation string, duration time.Duration, err error) {
+	externalRequestsTotal.WithLabelValues(service, operation, outcome(err)).Inc()
+	externalRequestDuration.WithLabelValues(service, operation).Observe(duration.Seconds())
+}
+
+// Handler returns http.Handler exposing metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+//
+// Instrumentation
+//
+
+var errServerError = errors.New("server error")
+
+type roundTripper struct {
+	service   string
+	operation func(*http.Request) string
+	next      http.RoundTripper
+}
+
+// InstrumentRoundTripper returns http.RoundTripper recording requests to the service.
+// operation returns the name of the operation of the request.
+// Responses with status code 5xx are regarded as failures.
+func InstrumentRoundTripper(service string, operation func(*http.Request) string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{service, operation, next}
+}
+
+// Operation returns the function returning always op
+func Operation(op string) func(*http.Request) string {
+	return func(*http.Request) string { return op }
+}
+
+// OperationFromPath returns the last element of the request path
+// (e.g. "chat.postMessage" of "/api/chat.postMessage")
+func OperationFromPath(r *http.Request) string {
+	return path.Base(r.URL.Path)
+}
+
+func (rt *roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(r)
+	failed := err
+	if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+		failed = errServerError
+	}
+	ObserveExternal(rt.service, rt.operation(r), time.Since(start), failed)
+	return resp, err
+}
+
+// UnaryClientInterceptor returns grpc.UnaryClientInterceptor recording requests to the service.
+// The operation is the name of the method (e.g. "ListScene").
+func UnaryClientInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		ObserveExternal(service, path.Base(method), time.Since(start), err)
+		return err
+	}
+}
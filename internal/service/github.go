@@ -7,22 +7,34 @@ import (
 	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 
 	"github.com/cloudnativedaysjp/seaman/internal/infra/gitcommand"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
+	"github.com/cloudnativedaysjp/seaman/internal/tracing"
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
 )
 
+var tracer = otel.Tracer("github.com/cloudnativedaysjp/seaman/internal/service")
+
+// attributes of spans
+const (
+	attrRepository = attribute.Key("github.repository")
+	attrBaseBranch = attribute.Key("github.base_branch")
+)
+
 type GitHubIface interface {
 	CreatePullRequestWithEmptyCommit(ctx context.Context,
 		org, repo, level string,
-		targetBaseBranch string, headBranchSuffix string,
+		targetBaseBranch string, headBranchSuffix string, notes string,
 	) (prNum int, err error)
 
 	SeparatePullRequests(ctx context.Context,
-		org, repo string, prNum int, targetBaseBranch string, prBranch string,
-	) (prNumDev, prNumProd int, err error)
+		org, repo string, prNum int, targetBaseBranch string, prBranch string, environments []string,
+	) (prNums []int, err error)
 }
 
 type GitHub struct {
@@ -39,11 +51,16 @@ func NewGitHubService(
 
 func (s *GitHub) CreatePullRequestWithEmptyCommit(ctx context.Context,
 	org, repo, level string,
-	targetBaseBranch string, headBranchSuffix string,
-) (int, error) {
+	targetBaseBranch string, headBranchSuffix string, notes string,
+) (_ int, err error) {
 	const (
 		emptyPrHeadBranchPrefix = "seaman/release_"
 	)
+	ctx, span := tracer.Start(ctx, "GitHub.CreatePullRequestWithEmptyCommit", trace.WithAttributes(
+		attrRepository.String(org+"/"+repo), attrBaseBranch.String(targetBaseBranch),
+		attribute.String("release.level", level),
+	))
+	defer func() { tracing.End(span, err) }()
 	logger := log.FromContext(ctx)
 	headBranchName := emptyPrHeadBranchPrefix + headBranchSuffix
 
@@ -77,8 +94,12 @@ func (s *GitHub) CreatePullRequestWithEmptyCommit(ctx context.Context,
 	//
 	// create PR -> label
 	//
+	body := "Automatic Release"
+	if notes != "" {
+		body += "\n\n" + notes
+	}
 	prNum, err := s.githubapi.CreatePullRequest(ctx, org, repo, headBranchName,
-		targetBaseBranch, "[dreamkast-releasebot] Automatic Release", "Automatic Release")
+		targetBaseBranch, "[dreamkast-releasebot] Automatic Release", body)
 	if err != nil {
 		return 0, xerrors.Errorf("githubapi.CreatePullRequest failed: %w", err)
 	}
@@ -89,35 +110,65 @@ func (s *GitHub) CreatePullRequestWithEmptyCommit(ctx context.Context,
 	return prNum, nil
 }
 
+// SeparatePullRequests creates a PullRequest for each environment (e.g. "development", "production").
+// The original PullRequest is closed when the last one is merged if all of environments are separated.
 func (s *GitHub) SeparatePullRequests(ctx context.Context,
-	org, repo string, prNum int, targetBaseBranch string, prBranch string,
-) (int, int, error) {
+	org, repo string, prNum int, targetBaseBranch string, prBranch string, environments []string,
+) (_ []int, err error) {
+	ctx, span := tracer.Start(ctx, "GitHub.SeparatePullRequests", trace.WithAttributes(
+		attrRepository.String(org+"/"+repo), attrBaseBranch.String(targetBaseBranch),
+		attribute.Int("github.pull_request", prNum),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	title, changedFilepaths, err := s.githubapi.GetPullRequestTitleAndChangedFilepaths(ctx, org, repo, prNum)
 	if err != nil {
-		return 0, 0, xerrors.Errorf("githubapi.GetPullRequestChangedFilepaths failed: %w", err)
-	}
-	prNumDev, err := s.separatePullRequest(ctx,
-		org, repo, targetBaseBranch, prBranch, title, changedFilepaths, "development")
-	if err != nil {
-		return 0, 0, xerrors.Errorf("separatePullRequest(dev) failed: %w", err)
-	}
-	prNumProd, err := s.separatePullRequest(ctx,
-		org, repo, targetBaseBranch, prBranch, title, changedFilepaths, "production")
-	if err != nil {
-		return 0, 0, xerrors.Errorf("separatePullRequest(prod) failed: %w", err)
+		return nil, xerrors.Errorf("githubapi.GetPullRequestChangedFilepaths failed: %w", err)
+	}
+	var prNums []int
+	for _, environment := range environments {
+		n, err := s.separatePullRequest(ctx,
+			org, repo, targetBaseBranch, prBranch, title, changedFilepaths, environment)
+		if err != nil {
+			return nil, xerrors.Errorf("separatePullRequest(%s) failed: %w", environment, err)
+		}
+		prNums = append(prNums, n)
 	}
 
-	if err := s.githubapi.UpdatePullRequestBody(ctx, org, repo, prNumProd, fmt.Sprintf(`Closes #%d`, prNum)); err != nil {
-		return 0, 0, xerrors.Errorf("githubapi.CreateIssueComment failed: %w", err)
+	if len(prNums) != 0 && s.coversAllEnvironments(changedFilepaths, environments) {
+		if err := s.githubapi.UpdatePullRequestBody(ctx, org, repo, prNums[len(prNums)-1], fmt.Sprintf(`Closes #%d`, prNum)); err != nil {
+			return nil, xerrors.Errorf("githubapi.CreateIssueComment failed: %w", err)
+		}
 	}
 
-	return prNumDev, prNumProd, nil
+	return prNums, nil
+}
+
+// coversAllEnvironments returns true if every changed file belongs to any of environments
+func (s *GitHub) coversAllEnvironments(changedFilepaths []string, environments []string) bool {
+	for _, fpath := range changedFilepaths {
+		covered := false
+		for _, environment := range environments {
+			if strings.Contains(fpath, fmt.Sprintf("/%s/", environment)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *GitHub) separatePullRequest(ctx context.Context,
 	org, repo string, targetBaseBranch string, prBranch string,
 	title string, changedFilepaths []string, environment string,
-) (int, error) {
+) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "GitHub.separatePullRequest", trace.WithAttributes(
+		attribute.String("github.environment", environment),
+	))
+	defer func() { tracing.End(span, err) }()
 	logger := log.FromContext(ctx)
 	headBranch := fmt.Sprintf("%s_%s", prBranch, environment)
 
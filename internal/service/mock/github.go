@@ -35,32 +35,31 @@ func (m *MockGitHubIface) EXPECT() *MockGitHubIfaceMockRecorder {
 }
 
 // CreatePullRequestWithEmptyCommit mocks base method.
-func (m *MockGitHubIface) CreatePullRequestWithEmptyCommit(ctx context.Context, org, repo, level, targetBaseBranch, headBranchSuffix string) (int, error) {
+func (m *MockGitHubIface) CreatePullRequestWithEmptyCommit(ctx context.Context, org, repo, level, targetBaseBranch, headBranchSuffix, notes string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePullRequestWithEmptyCommit", ctx, org, repo, level, targetBaseBranch, headBranchSuffix)
+	ret := m.ctrl.Call(m, "CreatePullRequestWithEmptyCommit", ctx, org, repo, level, targetBaseBranch, headBranchSuffix, notes)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePullRequestWithEmptyCommit indicates an expected call of CreatePullRequestWithEmptyCommit.
-func (mr *MockGitHubIfaceMockRecorder) CreatePullRequestWithEmptyCommit(ctx, org, repo, level, targetBaseBranch, headBranchSuffix any) *gomock.Call {
+func (mr *MockGitHubIfaceMockRecorder) CreatePullRequestWithEmptyCommit(ctx, org, repo, level, targetBaseBranch, headBranchSuffix, notes any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequestWithEmptyCommit", reflect.TypeOf((*MockGitHubIface)(nil).CreatePullRequestWithEmptyCommit), ctx, org, repo, level, targetBaseBranch, headBranchSuffix)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequestWithEmptyCommit", reflect.TypeOf((*MockGitHubIface)(nil).CreatePullRequestWithEmptyCommit), ctx, org, repo, level, targetBaseBranch, headBranchSuffix, notes)
 }
 
 // SeparatePullRequests mocks base method.
-func (m *MockGitHubIface) SeparatePullRequests(ctx context.Context, org, repo string, prNum int, targetBaseBranch, prBranch string) (int, int, error) {
+func (m *MockGitHubIface) SeparatePullRequests(ctx context.Context, org, repo string, prNum int, targetBaseBranch, prBranch string, environments []string) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SeparatePullRequests", ctx, org, repo, prNum, targetBaseBranch, prBranch)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "SeparatePullRequests", ctx, org, repo, prNum, targetBaseBranch, prBranch, environments)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SeparatePullRequests indicates an expected call of SeparatePullRequests.
-func (mr *MockGitHubIfaceMockRecorder) SeparatePullRequests(ctx, org, repo, prNum, targetBaseBranch, prBranch any) *gomock.Call {
+func (mr *MockGitHubIfaceMockRecorder) SeparatePullRequests(ctx, org, repo, prNum, targetBaseBranch, prBranch, environments any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeparatePullRequests", reflect.TypeOf((*MockGitHubIface)(nil).SeparatePullRequests), ctx, org, repo, prNum, targetBaseBranch, prBranch)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeparatePullRequests", reflect.TypeOf((*MockGitHubIface)(nil).SeparatePullRequests), ctx, org, repo, prNum, targetBaseBranch, prBranch, environments)
 }
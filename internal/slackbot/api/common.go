@@ -1,7 +1,35 @@
 package api
 
+import (
+	"bytes"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
 const (
 	// Action IDs
 	ActIdCommon_NothingToDo = "common_nothing"
 	ActIdCommon_Cancel      = "common_cancel"
 )
+
+// encodeValue encodes the fields of callback value in JSON,
+// so that they can contain any characters
+func encodeValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// callback values consist of strings and integers only
+		panic(err)
+	}
+	return string(b)
+}
+
+// decodeValue decodes callback value encoded by encodeValue
+func decodeValue(str string, v any) error {
+	dec := json.NewDecoder(bytes.NewReader([]byte(str)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return xerrors.Errorf("callbackValue (%s) is not expected: %w", str, err)
+	}
+	return nil
+}
@@ -1,35 +1,62 @@
 package api
 
-import (
-	"fmt"
-	"strconv"
-	"strings"
-
-	"golang.org/x/xerrors"
-)
-
 const (
 	// Action IDs
-	ActIdEmtec_SceneNext = "emtec_scenenext"
+	ActIdEmtec_SceneNext                 = "emtec_scenenext"
+	ActIdEmtec_SceneUndo                 = "emtec_sceneundo"
+	ActIdEmtec_DashboardToggleAutomation = "emtec_dashboard_toggleautomation"
+	ActIdEmtec_DashboardSceneNext        = "emtec_dashboard_scenenext"
+	ActIdEmtec_DashboardRefresh          = "emtec_dashboard_refresh"
+	ActIdEmtec_SelectedTrackEnable       = "emtec_selectedtrack_enable"
+	ActIdEmtec_SelectedTrackDisable      = "emtec_selectedtrack_disable"
+	ActIdEmtec_SwitchAllEnable           = "emtec_switchall_enable"
+	ActIdEmtec_SwitchAllDisable          = "emtec_switchall_disable"
 )
 
 type Track struct {
-	Id   int32
-	Name string
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+	// Event is the name of EMTEC event (empty if only one event is configured)
+	Event string `json:"event,omitempty"`
 }
 
 func NewTrack(str string) (Track, error) {
-	s := strings.Split(str, "__")
-	if len(s) != 2 {
-		return Track{}, xerrors.Errorf("callbackValue (%s) is not expected", str)
+	var track Track
+	if err := decodeValue(str, &track); err != nil {
+		return Track{}, err
 	}
-	trackId, err := strconv.Atoi(s[0])
-	if err != nil {
-		return Track{}, xerrors.Errorf("callbackValue (%s) is not expected", str)
-	}
-	return Track{int32(trackId), s[1]}, nil
+	return track, nil
 }
 
 func (m Track) String() string {
-	return fmt.Sprintf("%d__%s", m.Id, m.Name)
+	return encodeValue(m)
+}
+
+// SceneNext is the callback value for switching the scene of Track.
+// SceneIndex is the index of the current scene when the button was rendered,
+// which is used for ignoring the click if the scene has already been switched.
+type SceneNext struct {
+	Track
+	// SceneIndex is -1 if unknown
+	SceneIndex int32 `json:"sceneIndex"`
+}
+
+// NewSceneNext parses the value of SceneNext. The value of Track is also
+// accepted, whose SceneIndex is -1.
+func NewSceneNext(str string) (SceneNext, error) {
+	value := struct {
+		Track
+		SceneIndex *int32 `json:"sceneIndex"`
+	}{}
+	if err := decodeValue(str, &value); err != nil {
+		return SceneNext{}, err
+	}
+	if value.SceneIndex == nil {
+		return SceneNext{value.Track, -1}, nil
+	}
+	return SceneNext{value.Track, *value.SceneIndex}, nil
+}
+
+func (m SceneNext) String() string {
+	return encodeValue(m)
 }
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrack(t *testing.T) {
+	t.Run("names containing the separator of the old format", func(t *testing.T) {
+		track := Track{Id: 1, Name: "A__B", Event: "cndt__2023"}
+		got, err := NewTrack(track.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(track, got); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("old format", func(t *testing.T) {
+		if _, err := NewTrack("1__A__cndt2023"); err == nil {
+			t.Error("NewTrack() error = nil")
+		}
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := NewTrack(SceneNext{Track{Id: 1, Name: "A"}, 0}.String()); err == nil {
+			t.Error("NewTrack() error = nil")
+		}
+	})
+}
+
+func TestSceneNext(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want SceneNext
+	}{
+		{
+			name: "scene next",
+			str:  SceneNext{Track{Id: 1, Name: "A__B", Event: "cndt2023"}, 0}.String(),
+			want: SceneNext{Track{Id: 1, Name: "A__B", Event: "cndt2023"}, 0},
+		},
+		{
+			name: "track",
+			str:  Track{Id: 1, Name: "A"}.String(),
+			want: SceneNext{Track{Id: 1, Name: "A"}, -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSceneNext(tt.str)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies errors returned by controllers, which determines
+// the message shown to the user
+type ErrorKind string
+
+const (
+	ErrorKindInternal     ErrorKind = "InternalServerError"
+	ErrorKindInvalidInput ErrorKind = "InvalidArguments"
+	ErrorKindNotFound     ErrorKind = "NotFound"
+	ErrorKindForbidden    ErrorKind = "Forbidden"
+	ErrorKindUnavailable  ErrorKind = "Unavailable"
+)
+
+// Error is the error with its kind and the message which is safe to show in Slack
+type Error struct {
+	Kind ErrorKind
+	// Message is shown to the user. It must not contain secrets.
+	// It is the key of the message (see Msg* constants) which is translated and
+	// formatted with Args by view, or the message itself if it is not a key.
+	Message string
+	Args    []any
+	// Err is the cause, which is only logged
+	Err error
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if len(e.Args) != 0 {
+		message += fmt.Sprintf(" %v", e.Args)
+	}
+	if e.Err == nil {
+		return fmt.Sprintf("%s: %s", e.Kind, message)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Kind, message, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func InvalidInput(message string, args ...any) error {
+	return &Error{Kind: ErrorKindInvalidInput, Message: message, Args: args}
+}
+
+func NotFound(message string, err error, args ...any) error {
+	return &Error{Kind: ErrorKindNotFound, Message: message, Args: args, Err: err}
+}
+
+func Forbidden(message string, err error, args ...any) error {
+	return &Error{Kind: ErrorKindForbidden, Message: message, Args: args, Err: err}
+}
+
+func Unavailable(message string, err error, args ...any) error {
+	return &Error{Kind: ErrorKindUnavailable, Message: message, Args: args, Err: err}
+}
+
+// ErrorOf returns the classified error in the chain of err.
+// If err is not classified, it returns the error of ErrorKindInternal.
+func ErrorOf(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Kind: ErrorKindInternal, Err: err}
+}
+
+// reportedError is the error which was already shown to the user
+type reportedError struct {
+	err error
+}
+
+func (e reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e reportedError) Unwrap() error {
+	return e.err
+}
+
+// Reported marks err as already shown to the user by the controller,
+// so that the error handler only logs it
+func Reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return reportedError{err}
+}
+
+// IsReported reports whether err is marked by Reported
+func IsReported(err error) bool {
+	var e reportedError
+	return errors.As(err, &e)
+}
@@ -0,0 +1,32 @@
+package api
+
+// keys of the messages of Error, which are translated by the catalog of view
+const (
+	MsgInvalidCallbackValue = "error.invalidCallbackValue"
+	MsgCallbackOutdated     = "error.callbackOutdated"
+	MsgCallbackTampered     = "error.callbackTampered"
+	MsgCallbackExpired      = "error.callbackExpired"
+	MsgGitHubUnavailable    = "error.gitHubUnavailable"
+
+	MsgReleaseInvalidLevel = "error.release.invalidLevel"
+	MsgReleaseNotTarget    = "error.release.notTarget"
+
+	MsgEmtecEventRequired       = "error.emtec.eventRequired"
+	MsgEmtecEventUnknown        = "error.emtec.eventUnknown"
+	MsgEmtecUnavailable         = "error.emtec.unavailable"
+	MsgEmtecUnavailableState    = "error.emtec.unavailableState"
+	MsgEmtecTrackRequired       = "error.emtec.trackRequired"
+	MsgEmtecTrackNotFound       = "error.emtec.trackNotFound"
+	MsgEmtecTrackAmbiguous      = "error.emtec.trackAmbiguous"
+	MsgEmtecScheduleUsage       = "error.emtec.scheduleUsage"
+	MsgEmtecScheduleTimeInvalid = "error.emtec.scheduleTimeInvalid"
+	MsgEmtecScheduleTimePast    = "error.emtec.scheduleTimePast"
+	MsgEmtecScheduleIdRequired  = "error.emtec.scheduleIdRequired"
+	MsgEmtecScheduleIdInvalid   = "error.emtec.scheduleIdInvalid"
+	MsgEmtecScheduleNotFound    = "error.emtec.scheduleNotFound"
+
+	MsgAuditInvalidCount = "error.audit.invalidCount"
+
+	MsgWebhookDeliveryRequired = "error.webhook.deliveryRequired"
+	MsgWebhookDeliveryNotFound = "error.webhook.deliveryNotFound"
+)
@@ -14,23 +14,57 @@ const (
 	ActIdRelease_SelectedLevelMinor = "release_selected_level_minor"
 	ActIdRelease_SelectedLevelPatch = "release_selected_level_patch"
 	ActIdRelease_OK                 = "release_ok"
+	ActIdRelease_OpenModal          = "release_open_modal"
+	// Callback IDs of modal
+	CallbackIdRelease_Modal = "release_modal"
+	// Block IDs (and Action IDs) of inputs in modal
+	BlockIdRelease_Repository = "release_repo"
+	BlockIdRelease_Level      = "release_level"
+	BlockIdRelease_Notes      = "release_notes"
 	// Callback Values
 	CallbackValueRelease_VersionMajor = "release/major"
 	CallbackValueRelease_VersionMinor = "release/minor"
 	CallbackValueRelease_VersionPatch = "release/patch"
 )
 
+// IsReleaseLevel reports whether level is one of CallbackValueRelease_Version*
+func IsReleaseLevel(level string) bool {
+	switch level {
+	case CallbackValueRelease_VersionMajor, CallbackValueRelease_VersionMinor, CallbackValueRelease_VersionPatch:
+		return true
+	}
+	return false
+}
+
 type OrgRepo struct {
 	org  string
 	repo string
 }
 
+// orgRepoLevelValue is the callback value of OrgRepo and OrgRepoLevel
+type orgRepoLevelValue struct {
+	Org   string `json:"org"`
+	Repo  string `json:"repo"`
+	Level string `json:"level,omitempty"`
+}
+
+func OrgRepoOf(org, repo string) OrgRepo {
+	return OrgRepo{org, repo}
+}
+
 func NewOrgRepo(str string) (OrgRepo, error) {
-	s := strings.Split(str, "__")
-	if len(s) != 2 {
+	var v orgRepoLevelValue
+	if err := decodeValue(str, &v); err != nil {
+		return OrgRepo{}, err
+	}
+	if v.Org == "" || v.Repo == "" || v.Level != "" {
 		return OrgRepo{}, xerrors.Errorf("callbackValue (%s) is not expected", str)
 	}
-	return OrgRepo{s[0], s[1]}, nil
+	return OrgRepo{v.Org, v.Repo}, nil
+}
+
+func (m OrgRepo) String() string {
+	return encodeValue(orgRepoLevelValue{Org: m.org, Repo: m.repo})
 }
 
 func (m OrgRepo) Org() string {
@@ -59,17 +93,39 @@ type OrgRepoLevel struct {
 }
 
 func NewOrgRepoLevel(str string) (OrgRepoLevel, error) {
-	s := strings.Split(str, "__")
-	if len(s) != 3 {
+	var v orgRepoLevelValue
+	if err := decodeValue(str, &v); err != nil {
+		return OrgRepoLevel{}, err
+	}
+	if v.Org == "" || v.Repo == "" || !IsReleaseLevel(v.Level) {
 		return OrgRepoLevel{}, xerrors.Errorf("callbackValue (%s) is not expected", str)
 	}
-	return OrgRepoLevel{OrgRepo{s[0], s[1]}, s[2]}, nil
+	return OrgRepoLevel{OrgRepo{v.Org, v.Repo}, v.Level}, nil
 }
 
 func (m OrgRepoLevel) String() string {
-	return fmt.Sprintf("%s__%s__%s", m.org, m.repo, m.level)
+	return encodeValue(orgRepoLevelValue{Org: m.org, Repo: m.repo, Level: m.level})
 }
 
 func (m OrgRepoLevel) Level() string {
 	return m.level
 }
+
+// ReleaseModalMetadata is private_metadata of the modal, which refers
+// to the message from which the modal was opened
+type ReleaseModalMetadata struct {
+	ChannelId string
+	MessageTs string
+}
+
+func NewReleaseModalMetadata(str string) (ReleaseModalMetadata, error) {
+	s := strings.Split(str, "__")
+	if len(s) != 2 {
+		return ReleaseModalMetadata{}, xerrors.Errorf("privateMetadata (%s) is not expected", str)
+	}
+	return ReleaseModalMetadata{s[0], s[1]}, nil
+}
+
+func (m ReleaseModalMetadata) String() string {
+	return fmt.Sprintf("%s__%s", m.ChannelId, m.MessageTs)
+}
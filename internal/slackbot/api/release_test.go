@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestOrgRepoLevel(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		orgRepoLevel := OrgRepoOf("cloudnativedaysjp", "dreamkast__ui").WithLevel(CallbackValueRelease_VersionMinor)
+		got, err := NewOrgRepoLevel(orgRepoLevel.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != orgRepoLevel {
+			t.Errorf("NewOrgRepoLevel() = %v", got)
+		}
+		orgRepo, err := NewOrgRepo(orgRepoLevel.OrgRepo.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if orgRepo != orgRepoLevel.OrgRepo {
+			t.Errorf("NewOrgRepo() = %v", orgRepo)
+		}
+	})
+	for name, str := range map[string]string{
+		"old format":     "cloudnativedaysjp__dreamkast__release/minor",
+		"unknown level":  OrgRepoOf("cloudnativedaysjp", "dreamkast").WithLevel("release/unknown").String(),
+		"without level":  OrgRepoOf("cloudnativedaysjp", "dreamkast").String(),
+		"without org":    `{"repo":"dreamkast","level":"release/minor"}`,
+		"unknown fields": `{"org":"cloudnativedaysjp","repo":"dreamkast","level":"release/minor","branch":"main"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewOrgRepoLevel(str); err == nil {
+				t.Error("NewOrgRepoLevel() error = nil")
+			}
+		})
+	}
+	t.Run("NewOrgRepo with level", func(t *testing.T) {
+		if _, err := NewOrgRepo(OrgRepoOf("a", "b").WithLevel(CallbackValueRelease_VersionMajor).String()); err == nil {
+			t.Error("NewOrgRepo() error = nil")
+		}
+	})
+}
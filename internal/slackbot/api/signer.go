@@ -0,0 +1,85 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// signatureVersion is the prefix of tokens, which is bumped when the format is changed
+const signatureVersion = "v1"
+
+// signatureLength is the length of truncated HMAC-SHA256 in bytes,
+// which keeps tokens within the limit of option values (150 characters)
+const signatureLength = 16
+
+// Signer signs callback values of interactive components so that controllers
+// can trust them. The token is "v1.<expiry (unix time)>.<signature>.<value>".
+type Signer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSigner returns the Signer whose tokens expire after ttl.
+// If key is empty, a random key is generated, so that tokens are invalidated on restart.
+func NewSigner(key []byte, ttl time.Duration) *Signer {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Signer{key: key, ttl: ttl, now: time.Now}
+}
+
+// WithNow replaces the clock (for tests)
+func (s *Signer) WithNow(now func() time.Time) *Signer {
+	s.now = now
+	return s
+}
+
+// Sign returns the token of value
+func (s *Signer) Sign(value string) string {
+	expiry := strconv.FormatInt(s.now().Add(s.ttl).Unix(), 10)
+	return strings.Join([]string{signatureVersion, expiry, s.signature(expiry, value), value}, ".")
+}
+
+// Verify returns the value of token. It returns the error of ErrorKindInvalidInput
+// if token is tampered, expired, or signed in the unknown version.
+func (s *Signer) Verify(token string) (string, error) {
+	t := strings.SplitN(token, ".", 4)
+	if len(t) != 4 || t[0] != signatureVersion {
+		return "", &Error{Kind: ErrorKindInvalidInput,
+			Message: MsgCallbackOutdated,
+			Err:     xerrors.Errorf("callbackValue (%s) is not signed in %s", token, signatureVersion)}
+	}
+	expiry, signature, value := t[1], t[2], t[3]
+	if !hmac.Equal([]byte(signature), []byte(s.signature(expiry, value))) {
+		return "", &Error{Kind: ErrorKindInvalidInput,
+			Message: MsgCallbackTampered,
+			Err:     xerrors.Errorf("signature of callbackValue (%s) is invalid", token)}
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", &Error{Kind: ErrorKindInvalidInput,
+			Message: MsgCallbackTampered,
+			Err:     xerrors.Errorf("expiry of callbackValue (%s) is not expected", token)}
+	}
+	if s.now().After(time.Unix(unix, 0)) {
+		return "", &Error{Kind: ErrorKindInvalidInput,
+			Message: MsgCallbackExpired,
+			Err:     xerrors.Errorf("callbackValue (%s) expired at %s", token, time.Unix(unix, 0).Format(time.RFC3339))}
+	}
+	return value, nil
+}
+
+func (s *Signer) signature(expiry, value string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(signatureVersion + "." + expiry + "." + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:signatureLength])
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte("secret"), time.Hour).WithNow(func() time.Time { return now })
+	token := signer.Sign("cloudnativedaysjp__seaman__release/minor")
+
+	t.Run("sign and verify", func(t *testing.T) {
+		if !strings.HasPrefix(token, "v1.") {
+			t.Errorf("token %q is not versioned", token)
+		}
+		value, err := signer.Verify(token)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if value != "cloudnativedaysjp__seaman__release/minor" {
+			t.Errorf("Verify() = %q", value)
+		}
+	})
+	t.Run("value containing separators", func(t *testing.T) {
+		value, err := signer.Verify(signer.Sign("1__A.B.C__cndt"))
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if value != "1__A.B.C__cndt" {
+			t.Errorf("Verify() = %q", value)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		message string
+	}{
+		{
+			name:    "plain value",
+			token:   "cloudnativedaysjp__seaman__release/minor",
+			now:     now,
+			message: MsgCallbackOutdated,
+		},
+		{
+			name:    "unknown version",
+			token:   "v0" + strings.TrimPrefix(token, "v1"),
+			now:     now,
+			message: MsgCallbackOutdated,
+		},
+		{
+			name:    "tampered value",
+			token:   strings.Replace(token, "seaman", "dreamkast", 1),
+			now:     now,
+			message: MsgCallbackTampered,
+		},
+		{
+			name:    "tampered expiry",
+			token:   strings.Replace(token, "v1.1", "v1.2", 1),
+			now:     now,
+			message: MsgCallbackTampered,
+		},
+		{
+			name:    "expired",
+			token:   token,
+			now:     now.Add(time.Hour + time.Second),
+			message: MsgCallbackExpired,
+		},
+		{
+			name:    "signed by another key",
+			token:   NewSigner([]byte("another"), time.Hour).Sign("cloudnativedaysjp__seaman__release/minor"),
+			now:     now,
+			message: MsgCallbackTampered,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSigner([]byte("secret"), time.Hour).WithNow(func() time.Time { return tt.now })
+			_, err := s.Verify(tt.token)
+			if err == nil {
+				t.Fatalf("Verify() error = nil")
+			}
+			if kind := ErrorOf(err).Kind; kind != ErrorKindInvalidInput {
+				t.Errorf("Verify() error kind = %s", kind)
+			}
+			if message := ErrorOf(err).Message; message != tt.message {
+				t.Errorf("Verify() error message = %s", message)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/exp/slog"
+	"golang.org/x/xerrors"
+
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+const (
+	defaultAuditEntries = 10
+	maxAuditEntries     = 50
+)
+
+// auditEntry returns audit.Entry of the action requested by the Slack user
+func auditEntry(userId, action, target string, err error) audit.Entry {
+	return audit.Entry{
+		Source: audit.SourceSlack, Actor: userId, Action: action, Target: target, Outcome: audit.OutcomeOf(err),
+	}
+}
+
+type AuditController struct {
+	slackFactory infra_slack.SlackClientFactory
+	audit        *audit.Logger
+	log          *slog.Logger
+}
+
+func NewAuditController(
+	logger *slog.Logger,
+	slackFactory infra_slack.SlackClientFactory,
+	auditor *audit.Logger,
+) *AuditController {
+	return &AuditController{slackFactory, auditor, logger}
+}
+
+// ShowRecent handles "@seaman audit [n]"
+func (c *AuditController) ShowRecent(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	// parse arguments
+	n := defaultAuditEntries
+	if s := strings.Fields(ev.Text); len(s) >= 3 {
+		n, err = strconv.Atoi(s[2])
+		if err != nil || n <= 0 || n > maxAuditEntries {
+			return api.InvalidInput(api.MsgAuditInvalidCount, maxAuditEntries, "audit [n]")
+		}
+	}
+
+	msg, err := view.AuditRecent(i18n.FromContext(ctx), c.audit.Recent(n))
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
@@ -2,27 +2,45 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/exp/slog"
 	"golang.org/x/xerrors"
 
+	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
 	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/lacks"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
 )
 
+var tracer = otel.Tracer("github.com/cloudnativedaysjp/seaman/internal/slackbot/controller")
+
 type CommonController struct {
 	slackFactory infra_slack.SlackClientFactory
 	log          *slog.Logger
+
+	reply ReplyPolicy
 }
 
 func NewCommonController(
 	logger *slog.Logger,
 	slackFactory infra_slack.SlackClientFactory,
 ) *CommonController {
-	return &CommonController{slackFactory, logger}
+	return &CommonController{slackFactory: slackFactory, log: logger}
+}
+
+// WithReplyPolicy sets ReplyPolicy deciding where errors and the help are posted
+func (c *CommonController) WithReplyPolicy(policy ReplyPolicy) *CommonController {
+	c.reply = policy
+	return c
 }
 
 func (c *CommonController) ShowCommands(ctx context.Context,
@@ -30,18 +48,23 @@ func (c *CommonController) ShowCommands(ctx context.Context,
 	client *socketmode.Client, subcommands map[string]string,
 ) error {
 	channelId := ev.Channel
-	messageTs := ev.TimeStamp
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	if err := sc.PostMessage(ctx, channelId,
-		view.ShowCommands(subcommands),
-	); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
+	msg, err := view.ShowCommands(i18n.FromContext(ctx), subcommands)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if evt, ok := lacks.EventFromContext(ctx); ok && c.reply.Ephemeral {
+		if err := sc.PostEphemeral(ctx, channelId, c.reply.threadTs(evt), ev.User, msg); err != nil {
+			return xerrors.Errorf("failed to post message: %w", err)
+		}
+		return nil
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
@@ -49,16 +72,17 @@ func (c *CommonController) ShowCommands(ctx context.Context,
 
 func (c *CommonController) ShowVersion(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
 	channelId := ev.Channel
-	messageTs := ev.TimeStamp
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	if err := sc.PostMessage(ctx, channelId, view.ShowVersion()); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
+	msg, err := view.ShowVersion()
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
@@ -74,13 +98,63 @@ func (c *CommonController) InteractionCancel(ctx context.Context, interaction sl
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("failed to initialize Slack client: %v", err)
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	if err := sc.UpdateMessage(ctx, channelId, messageTs, view.Canceled()); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("failed to post message: %v", err)
+	msg, err := view.Canceled(i18n.FromContext(ctx))
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
 }
+
+// HandleError notifies the user of the error returned by a handler.
+// Errors of commands are posted as the reply to the command, and errors of interactions
+// are posted to the thread of the message, so that the original message is kept.
+// If ReplyPolicy.Ephemeral is true, they are visible only to the requester.
+func (c *CommonController) HandleError(ctx context.Context, client *socketmode.Client, evt lacks.Event, err error) {
+	logger := log.FromContext(ctx)
+	if api.IsReported(err) || evt.ChannelId == "" {
+		return
+	}
+	// block_suggestion responds with the options instead of a message
+	if evt.Kind == string(slack.InteractionTypeBlockSuggestion) {
+		return
+	}
+	sc, scErr := c.slackFactory.New(client.Client)
+	if scErr != nil {
+		logger.Error(fmt.Sprintf("failed to initialize Slack client: %v", scErr))
+		return
+	}
+	msg, scErr := view.Error(i18n.FromContext(ctx), evt.MessageTs, err)
+	if scErr != nil {
+		logger.Error(fmt.Sprintf("failed to render message: %v", scErr))
+		return
+	}
+	switch {
+	case c.reply.Ephemeral && evt.UserId != "":
+		scErr = sc.PostEphemeral(ctx, evt.ChannelId, c.reply.threadTs(evt), evt.UserId, msg)
+	case evt.IsCommand() || evt.MessageTs == "":
+		scErr = sc.PostMessage(ctx, evt.ChannelId, msg)
+	default:
+		scErr = sc.PostMessageToThread(ctx, evt.ChannelId, evt.ThreadRoot(), msg)
+	}
+	if scErr != nil {
+		logger.Error(fmt.Sprintf("failed to post message: %v", scErr))
+	}
+}
+
+// gitHubError classifies the error of GitHub to show its reason to the user
+func gitHubError(err error) error {
+	var nf *githubapi.NotFoundError
+	if errors.As(err, &nf) {
+		return api.NotFound(nf.Detail, err)
+	}
+	if githubapi.IsTransientError(err) {
+		return api.Unavailable(api.MsgGitHubUnavailable, err)
+	}
+	return err
+}
@@ -3,10 +3,10 @@ package controller
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 	"golang.org/x/exp/slog"
@@ -15,47 +15,101 @@ import (
 
 	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
 
-	infra_cnd "github.com/cloudnativedaysjp/seaman/internal/infra/emtec-ecu"
 	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
-	"github.com/cloudnativedaysjp/seaman/pkg/log"
-	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
 type EmtecController struct {
-	slackFactory   infra_slack.SlackClientFactory
-	cndSceneClient pb.SceneServiceClient
-	cndTrackClient pb.TrackServiceClient
-	log            *slog.Logger
+	slackFactory infra_slack.SlackClientFactory
+	events       map[string]emtecClient
+	// eventNames is the ordered names of events
+	eventNames    []string
+	channelEvents map[string]string
+	audit         *audit.Logger
+	signer        *api.Signer
+	log           *slog.Logger
+
+	// dashboards is refreshed by RunPoller
+	dashboards *dashboardRegistry
+	// scheduler is executed by RunScheduler
+	scheduler *emtecScheduler
+	// location is the time zone in which schedules are read and shown
+	location *time.Location
+
+	// confirmTracks is the list of track names or IDs ("*" for all) which
+	// show the confirm dialog on switching the scene
+	confirmTracks []string
+	// undoWindow is the duration during which switching the scene can be undone
+	undoWindow time.Duration
+	// sceneMu serializes switching scenes for ignoring double clicks
+	sceneMu   sync.Mutex
+	pendingMu sync.Mutex
+	pending   map[string]*pendingSceneSwitch
+	// pendingWg counts the timers of pending switches which have not finished
+	pendingWg sync.WaitGroup
 }
 
 func NewEmtecController(
 	logger *slog.Logger,
 	slackFactory infra_slack.SlackClientFactory,
-	cndClient *infra_cnd.CndWrapper,
+	auditor *audit.Logger,
+	signer *api.Signer,
+	events []EmtecEvent,
 ) *EmtecController {
-	return &EmtecController{slackFactory, cndClient, cndClient, logger}
+	c := &EmtecController{
+		slackFactory:  slackFactory,
+		events:        make(map[string]emtecClient),
+		channelEvents: make(map[string]string),
+		audit:         auditor,
+		signer:        signer,
+		log:           logger,
+		dashboards:    &dashboardRegistry{},
+		scheduler:     newEmtecScheduler(),
+		location:      time.Local,
+		confirmTracks: []string{"*"},
+		pending:       make(map[string]*pendingSceneSwitch),
+	}
+	for _, event := range events {
+		label := event.Name
+		if len(events) == 1 {
+			label = ""
+		}
+		c.events[event.Name] = emtecClient{event.Name, label, event.Client, event.Client, event.Client}
+		c.eventNames = append(c.eventNames, event.Name)
+		for _, channelId := range event.Channels {
+			c.channelEvents[channelId] = event.Name
+		}
+	}
+	return c
 }
 
 func (c *EmtecController) ListTrack(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
 	channelId := ev.Channel
-	messageTs := ev.TimeStamp
 
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
+	_, eventName := emtecArgs(ev.Text)
+	ec, err := c.eventOf(channelId, eventName)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
 
-	resp, err := c.cndTrackClient.ListTrack(ctx, &emptypb.Empty{})
+	resp, err := ec.track.ListTrack(ctx, &emptypb.Empty{})
 	if err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("cndTrackClient.ListTrack failed: %w", err)
+		return xerrors.Errorf("cndTrackClient.ListTrack failed: %w", emtecError(err))
 	}
 
-	if err := sc.PostMessage(ctx, channelId, view.EmtecListTrack(resp.Tracks)); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
+	msg, err := view.EmtecListTrack(resp.Tracks)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
@@ -76,105 +130,138 @@ func (c *EmtecController) DisableAutomation(ctx context.Context, ev *slackevents
 }
 
 func (c *EmtecController) switchAutomation(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client, enabled bool) error {
-	logger := log.FromContext(ctx)
 	channelId := ev.Channel
-	messageTs := ev.TimeStamp
 
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
-	// parse arguments
-	s := strings.Fields(ev.Text)
-	if len(s) < 4 {
-		msg := "args.length must be greater than 2"
-		logger.Debug(fmt.Sprintf("invalid input: %v", msg))
-		_ = sc.PostMessage(ctx, channelId, view.InvalidArguments(messageTs, msg))
-		return nil
-	}
-	trackIdStr := s[3]
-	trackId, err := strconv.Atoi(trackIdStr)
+	args, eventName := emtecArgs(ev.Text)
+	ec, err := c.eventOf(channelId, eventName)
 	if err != nil {
-		msg := "args[1] (trackId) must be integer"
-		logger.Debug(fmt.Sprintf("invalid input: %v", msg))
-		_ = sc.PostMessage(ctx, channelId, view.InvalidArguments(messageTs, msg))
-		return nil
+		return xerrors.Errorf("%w", err)
 	}
 
-	var msg slack.Msg
-	if enabled {
-		resp, err := c.cndTrackClient.EnableAutomation(ctx,
-			&pb.SwitchAutomationRequest{TrackId: int32(trackId)})
+	// without arguments or with "all", ask which tracks are switched
+	if len(args) == 0 || (len(args) == 1 && strings.EqualFold(args[0], "all")) {
+		resp, err := ec.track.ListTrack(ctx, &emptypb.Empty{})
 		if err != nil {
-			_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
-			return xerrors.Errorf("cndTrackClient.DisableAutomation failed: %w", err)
+			return xerrors.Errorf("cndTrackClient.ListTrack failed: %w", emtecError(err))
+		}
+		render := view.EmtecSelectTrack
+		if len(args) != 0 {
+			render = view.EmtecSwitchAllConfirmation
 		}
-		msg = view.EmtecEnabled(resp.TrackName)
-	} else {
-		resp, err := c.cndTrackClient.DisableAutomation(ctx,
-			&pb.SwitchAutomationRequest{TrackId: int32(trackId)})
+		msg, err := render(i18n.FromContext(ctx), c.signer, ec.label, resp.Tracks, enabled)
 		if err != nil {
-			_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
-			return xerrors.Errorf("cndTrackClient.DisableAutomation failed: %w", err)
+			return xerrors.Errorf("failed to render message: %w", err)
 		}
-		msg = view.EmtecDisabled(resp.TrackName)
+		if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+			return xerrors.Errorf("failed to post message: %w", err)
+		}
+		return nil
+	}
+
+	trackId, err := trackIdOf(ctx, ec, args, fmt.Sprintf("emtec %s <track ID or name>", automationCommand(enabled)))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	track, err := c.setAutomation(ctx, ec, trackId, enabled)
+	c.audit.Record(ctx, auditEntry(ev.User, automationAction(enabled), auditTarget(ec.label, strings.Join(args, " ")), err))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
 	}
 
+	render := view.EmtecDisabled
+	if enabled {
+		render = view.EmtecEnabled
+	}
+	msg, err := render(i18n.FromContext(ctx), track.TrackName)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
 	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
 }
 
-func (c *EmtecController) UpdateSceneToNext(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
-	logger := log.FromContext(ctx)
-	channelId := interaction.Container.ChannelID
-	messageTs := interaction.Container.MessageTs
-	sentUserId := interaction.User.ID
+func (c *EmtecController) ListScene(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
 
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
+	args, eventName := emtecArgs(ev.Text)
+	ec, err := c.eventOf(channelId, eventName)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	trackId, err := trackIdOf(ctx, ec, args, "emtec list-scene <track ID or name>")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
 
-	track, err := api.NewTrack(utils.GetCallbackValueOnButton(interaction))
+	track, scenes, err := c.getTrackAndScenes(ctx, ec, trackId)
 	if err != nil {
-		logger.Debug(fmt.Sprintf("invalid callback value: %v", err))
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return nil
+		return xerrors.Errorf("%w", err)
 	}
 
-	if _, err := c.cndSceneClient.MoveSceneToNext(
-		ctx, &pb.MoveSceneToNextRequest{TrackId: track.Id},
-	); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("cndSceneClient.MoveScneToNext failed: %w", err)
+	msg, err := view.EmtecListScene(i18n.FromContext(ctx), c.signer, ec.label, track, scenes, c.needsConfirmation(track))
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
 	}
+	return nil
+}
 
-	msg, err := view.EmtecMovedToNextScene(interaction.Message.Msg)
+// NextScene posts the confirmation message. The scene is switched by UpdateSceneToNext
+func (c *EmtecController) NextScene(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
-		msg := "invalid interactive message"
-		logger.Debug(fmt.Sprintf("invalid input: %v", msg))
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
-		return nil
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	args, eventName := emtecArgs(ev.Text)
+	ec, err := c.eventOf(channelId, eventName)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	trackId, err := trackIdOf(ctx, ec, args, "emtec next-scene <track ID or name>")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
 	}
 
-	if err := sc.UpdateMessage(
-		ctx, channelId, messageTs, msg,
-	); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("failed to post message: %w", err)
+	track, scenes, err := c.getTrackAndScenes(ctx, ec, trackId)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
 	}
 
-	if err := sc.PostMessageToThread(
-		ctx, channelId, messageTs, slack.Msg{
-			Text: fmt.Sprintf("Switching was pushed by <@%s>", sentUserId)},
-	); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
+	msg, err := view.EmtecNextSceneConfirmation(i18n.FromContext(ctx), c.signer, ec.label, track, scenes, c.needsConfirmation(track))
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
 }
+
+func (c *EmtecController) getTrackAndScenes(ctx context.Context, ec emtecClient, trackId int32) (*pb.Track, []*pb.Scene, error) {
+	track, err := ec.track.GetTrack(ctx, &pb.GetTrackRequest{TrackId: trackId})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cndTrackClient.GetTrack failed: %w", emtecError(err))
+	}
+	resp, err := ec.scene.ListScene(ctx, &pb.ListSceneRequest{TrackId: trackId})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cndSceneClient.ListScene failed: %w", emtecError(err))
+	}
+	return track, resp.Scene, nil
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+)
+
+// Dashboard posts the dashboard of all tracks, which is updated in place by interactions
+func (c *EmtecController) Dashboard(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	_, eventName := emtecArgs(ev.Text)
+	ec, err := c.eventOf(channelId, eventName)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	lang := i18n.FromContext(ctx)
+	msg, err := c.renderDashboard(ctx, lang, ec, channelId, "", ev.User)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	dashboardTs, err := sc.PostMessageAndGetTs(ctx, channelId, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	c.dashboards.register(dashboardMessage{ec.label, channelId, dashboardTs, lang})
+	return nil
+}
+
+func (c *EmtecController) DashboardToggleAutomation(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.dashboardAction(ctx, interaction, client, func(sc infra_slack.SlackClient, ec emtecClient, value api.SceneNext) (slack.Msg, error) {
+		track := value.Track
+		current, err := ec.track.GetTrack(ctx, &pb.GetTrackRequest{TrackId: track.Id})
+		if err != nil {
+			return slack.Msg{}, xerrors.Errorf("cndTrackClient.GetTrack failed: %w", emtecError(err))
+		}
+		enabled := !current.Enabled
+		_, err = c.setAutomation(ctx, ec, track.Id, enabled)
+		c.audit.Record(ctx, auditEntry(interaction.User.ID, automationAction(enabled), auditTarget(ec.label, track.Name), err))
+		if err != nil {
+			return slack.Msg{}, xerrors.Errorf("%w", err)
+		}
+		return view.EmtecAutomationSwitchedBy(i18n.FromContext(ctx), track.Name, enabled, interaction.User.ID)
+	})
+}
+
+// DashboardSceneNext switches the scene in the same way as UpdateSceneToNext.
+// If the undo window is positive, the undo button is shown in the dashboard until the switch.
+func (c *EmtecController) DashboardSceneNext(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+	userId := interaction.User.ID
+	lang := i18n.FromContext(ctx)
+	return c.dashboardAction(ctx, interaction, client, func(sc infra_slack.SlackClient, ec emtecClient, value api.SceneNext) (slack.Msg, error) {
+		if c.undoWindow <= 0 {
+			return c.dashboardSceneNext(ctx, ec, value, userId)
+		}
+		p := &pendingSceneSwitch{
+			restore: func(ctx context.Context) error {
+				return c.updateDashboard(ctx, sc, lang, ec, channelId, messageTs, userId)
+			},
+			target:    auditTarget(ec.label, value.Name),
+			sc:        sc,
+			channelId: channelId,
+			messageTs: messageTs,
+			userId:    userId,
+			lang:      lang,
+		}
+		c.startSceneSwitch(ctx, pendingKey(channelId, messageTs, value.Id), p, func(ctx context.Context) error {
+			note, err := c.dashboardSceneNext(ctx, ec, value, userId)
+			if err != nil {
+				return xerrors.Errorf("%w", err)
+			}
+			if err := c.updateDashboard(ctx, sc, lang, ec, channelId, messageTs, userId); err != nil {
+				return xerrors.Errorf("%w", err)
+			}
+			if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+				return xerrors.Errorf("failed to post message: %w", err)
+			}
+			return nil
+		})
+		// the dashboard shows the undo button, and the note is posted on the switch
+		return slack.Msg{}, nil
+	})
+}
+
+// dashboardSceneNext switches the scene and returns the note posted to the thread
+func (c *EmtecController) dashboardSceneNext(ctx context.Context, ec emtecClient, value api.SceneNext, userId string) (slack.Msg, error) {
+	switched, err := c.moveSceneToNext(ctx, ec, value, userId)
+	if err != nil {
+		return slack.Msg{}, xerrors.Errorf("%w", err)
+	}
+	if !switched {
+		return view.EmtecSceneSwitchIgnored(i18n.FromContext(ctx), value.Name, userId)
+	}
+	return view.EmtecSceneSwitchedBy(i18n.FromContext(ctx), value.Name, userId)
+}
+
+func (c *EmtecController) DashboardRefresh(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.dashboardAction(ctx, interaction, client, nil)
+}
+
+// dashboardAction runs f for the track in callback value, updates the dashboard
+// in place and posts the message returned by f to the thread (unless it is empty).
+func (c *EmtecController) dashboardAction(ctx context.Context,
+	interaction slack.InteractionCallback, client *socketmode.Client,
+	f func(sc infra_slack.SlackClient, ec emtecClient, value api.SceneNext) (slack.Msg, error),
+) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	v, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	// the refresh button has only the event name as its value
+	var value api.SceneNext
+	if f != nil {
+		value, err = api.NewSceneNext(v)
+		if err != nil {
+			return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+		}
+	} else {
+		value.Event = v
+	}
+	ec, err := c.eventOf(channelId, value.Event)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	var note slack.Msg
+	if f != nil {
+		note, err = f(sc, ec, value)
+		if err != nil {
+			return xerrors.Errorf("%w", err)
+		}
+	}
+
+	if err := c.updateDashboard(ctx, sc, i18n.FromContext(ctx), ec, channelId, messageTs, interaction.User.ID); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	if len(note.Blocks.BlockSet) > 0 {
+		if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+			return xerrors.Errorf("failed to post message: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateDashboard renders the dashboard in the message and registers it to be refreshed by the poller
+func (c *EmtecController) updateDashboard(ctx context.Context, sc infra_slack.SlackClient,
+	lang i18n.Lang, ec emtecClient, channelId, messageTs, updatedBy string,
+) error {
+	msg, err := c.renderDashboard(ctx, lang, ec, channelId, messageTs, updatedBy)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	c.dashboards.register(dashboardMessage{ec.label, channelId, messageTs, lang})
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+func (c *EmtecController) renderDashboard(ctx context.Context,
+	lang i18n.Lang, ec emtecClient, channelId, messageTs, updatedBy string,
+) (slack.Msg, error) {
+	tracks, err := c.dashboardTracks(ctx, ec)
+	if err != nil {
+		return slack.Msg{}, err
+	}
+	return view.EmtecDashboard(lang, c.signer, ec.label, c.withPendingSceneSwitches(tracks, channelId, messageTs), updatedBy, time.Now())
+}
+
+// withPendingSceneSwitches returns the copy of tracks in which the tracks whose
+// switches in the dashboard of messageTs are pending show the undo button
+func (c *EmtecController) withPendingSceneSwitches(tracks []view.EmtecDashboardTrack, channelId, messageTs string) []view.EmtecDashboardTrack {
+	result := make([]view.EmtecDashboardTrack, len(tracks))
+	for i, t := range tracks {
+		if c.isPendingSceneSwitch(pendingKey(channelId, messageTs, t.Track.TrackId)) {
+			t.UndoWindow = c.undoWindow
+		}
+		result[i] = t
+	}
+	return result
+}
+
+func (c *EmtecController) dashboardTracks(ctx context.Context, ec emtecClient) ([]view.EmtecDashboardTrack, error) {
+	resp, err := ec.track.ListTrack(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, xerrors.Errorf("cndTrackClient.ListTrack failed: %w", emtecError(err))
+	}
+	var tracks []view.EmtecDashboardTrack
+	for _, track := range resp.Tracks {
+		scenes, err := ec.scene.ListScene(ctx, &pb.ListSceneRequest{TrackId: track.TrackId})
+		if err != nil {
+			return nil, xerrors.Errorf("cndSceneClient.ListScene failed: %w", emtecError(err))
+		}
+		tracks = append(tracks, view.EmtecDashboardTrack{
+			Track: track, Scenes: scenes.Scene, Confirm: c.needsConfirmation(track),
+		})
+	}
+	return tracks, nil
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	infra_cnd "github.com/cloudnativedaysjp/seaman/internal/infra/emtec-ecu"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+)
+
+// EmtecEvent is EMTEC-ECU of an event (conference)
+type EmtecEvent struct {
+	Name   string
+	Client *infra_cnd.CndWrapper
+	// Channels is the list of channel IDs in which the event is used without --event
+	Channels []string
+}
+
+// emtecAvailability is satisfied by infra_cnd.CndWrapper
+type emtecAvailability interface {
+	Available() (bool, string)
+}
+
+// emtecClient is the client of EMTEC-ECU of an event
+type emtecClient struct {
+	name string
+	// label is the event name shown in messages and callback values (empty if only one event is configured)
+	label        string
+	scene        pb.SceneServiceClient
+	track        pb.TrackServiceClient
+	availability emtecAvailability
+}
+
+// auditTarget returns the target of audit log prefixed by the event label
+func auditTarget(label, target string) string {
+	if label == "" {
+		return target
+	}
+	return label + "/" + target
+}
+
+// emtecArgs parses "@seaman emtec <subcommand> [args...] [--event=NAME]"
+// and returns args and the event name.
+func emtecArgs(text string) ([]string, string) {
+	var args []string
+	var event string
+	s := strings.Fields(text)
+	if len(s) < 3 {
+		return nil, ""
+	}
+	for _, arg := range s[3:] {
+		if name, ok := strings.CutPrefix(arg, "--event="); ok {
+			event = name
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, event
+}
+
+// resolveEvent returns the client of the event. If name is empty, the event
+// mapped to the channel (or the only event) is used.
+// If failed, it returns the error of InvalidInput.
+func (c *EmtecController) resolveEvent(channelId, name string) (emtecClient, error) {
+	if name == "" {
+		name = c.channelEvents[channelId]
+	}
+	if name == "" && len(c.eventNames) == 1 {
+		name = c.eventNames[0]
+	}
+	if name == "" {
+		return emtecClient{}, api.InvalidInput(api.MsgEmtecEventRequired, strings.Join(c.eventNames, ", "))
+	}
+	ec, ok := c.events[name]
+	if !ok {
+		return emtecClient{}, api.InvalidInput(api.MsgEmtecEventUnknown, name, strings.Join(c.eventNames, ", "))
+	}
+	return ec, nil
+}
+
+// eventOf resolves the event and checks whether it is available.
+// If not, it returns the error of InvalidInput or Unavailable.
+func (c *EmtecController) eventOf(channelId, name string) (emtecClient, error) {
+	ec, err := c.resolveEvent(channelId, name)
+	if err != nil {
+		return emtecClient{}, err
+	}
+	if available, state := ec.availability.Available(); !available {
+		return emtecClient{}, api.Unavailable(api.MsgEmtecUnavailableState, nil, state)
+	}
+	return ec, nil
+}
+
+// emtecError classifies the error of EMTEC-ECU by its gRPC status
+func emtecError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return api.Unavailable(api.MsgEmtecUnavailable, err)
+	case codes.NotFound:
+		return api.NotFound(st.Message(), err)
+	case codes.PermissionDenied:
+		return api.Forbidden(st.Message(), err)
+	}
+	return err
+}
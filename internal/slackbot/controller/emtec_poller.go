@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+// maxDashboards is the number of dashboard messages refreshed by the poller
+const maxDashboards = 20
+
+type dashboardMessage struct {
+	// event is emtecClient.label
+	event     string
+	channelId string
+	messageTs string
+	// lang is the language of the user who posted or refreshed the dashboard last
+	lang i18n.Lang
+}
+
+// dashboardRegistry keeps dashboard messages which should be refreshed
+type dashboardRegistry struct {
+	mu       sync.Mutex
+	messages []dashboardMessage
+}
+
+func (r *dashboardRegistry) register(m dashboardMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, registered := range r.messages {
+		if registered.channelId == m.channelId && registered.messageTs == m.messageTs {
+			r.messages[i] = m
+			return
+		}
+	}
+	r.messages = append(r.messages, m)
+	if len(r.messages) > maxDashboards {
+		r.messages = r.messages[len(r.messages)-maxDashboards:]
+	}
+}
+
+func (r *dashboardRegistry) list() []dashboardMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]dashboardMessage{}, r.messages...)
+}
+
+// emtecTrackState is a snapshot of a track for detecting changes
+type emtecTrackState struct {
+	name         string
+	enabled      bool
+	currentScene string
+}
+
+func newEmtecState(tracks []view.EmtecDashboardTrack) map[int32]emtecTrackState {
+	state := make(map[int32]emtecTrackState)
+	for _, t := range tracks {
+		state[t.Track.TrackId] = emtecTrackState{t.Track.TrackName, t.Track.Enabled, t.CurrentScene()}
+	}
+	return state
+}
+
+// diffEmtecState returns changes from prev to curr
+func diffEmtecState(prev, curr map[int32]emtecTrackState) []view.EmtecChange {
+	var ids []int32
+	for id := range curr {
+		ids = append(ids, id)
+	}
+	for id := range prev {
+		if _, ok := curr[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var changes []view.EmtecChange
+	for _, id := range ids {
+		p, existedBefore := prev[id]
+		c, existsNow := curr[id]
+		switch {
+		case !existedBefore:
+			changes = append(changes, view.EmtecChange{Kind: view.EmtecTrackAdded, Track: c.name})
+		case !existsNow:
+			changes = append(changes, view.EmtecChange{Kind: view.EmtecTrackRemoved, Track: p.name})
+		default:
+			if p.enabled != c.enabled {
+				if c.enabled {
+					changes = append(changes, view.EmtecChange{Kind: view.EmtecAutomationEnabled, Track: c.name})
+				} else {
+					changes = append(changes, view.EmtecChange{Kind: view.EmtecAutomationDisabled, Track: c.name})
+				}
+			}
+			if p.currentScene != c.currentScene {
+				changes = append(changes, view.EmtecChange{Kind: view.EmtecSceneChanged,
+					Track: c.name, From: p.currentScene, To: c.currentScene})
+			}
+		}
+	}
+	return changes
+}
+
+// RunPoller polls EMTEC of all events periodically until ctx is done. If
+// changes are detected, registered dashboards are refreshed and changes are
+// posted to notificationChannel (if not empty) in lang.
+func (c *EmtecController) RunPoller(ctx context.Context,
+	client slack.Client, interval time.Duration, notificationChannel string, lang i18n.Lang,
+) {
+	logger := c.log.With("component", "emtecPoller")
+	ctx = log.IntoContext(ctx, logger)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := make(map[string]map[int32]emtecTrackState)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, name := range c.eventNames {
+			ec := c.events[name]
+			if ok, state := ec.availability.Available(); !ok {
+				logger.Debug(fmt.Sprintf("EMTEC of %s is unavailable, skipped: %s", name, state))
+				continue
+			}
+			tracks, err := c.dashboardTracks(ctx, ec)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("failed to poll EMTEC of %s: %v", name, err))
+				continue
+			}
+			curr := newEmtecState(tracks)
+			before, ok := prev[name]
+			prev[name] = curr
+			if !ok {
+				continue
+			}
+			changes := diffEmtecState(before, curr)
+			if len(changes) == 0 {
+				continue
+			}
+			logger.Info(fmt.Sprintf("detected %d change(s) of EMTEC of %s", len(changes), name))
+			c.notifyChanges(ctx, client, ec, tracks, changes, notificationChannel, lang)
+		}
+	}
+}
+
+func (c *EmtecController) notifyChanges(ctx context.Context, client slack.Client,
+	ec emtecClient, tracks []view.EmtecDashboardTrack, changes []view.EmtecChange,
+	notificationChannel string, lang i18n.Lang,
+) {
+	logger := log.FromContext(ctx)
+	sc, err := c.slackFactory.New(client)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to initialize Slack client: %v", err))
+		return
+	}
+	for _, m := range c.dashboards.list() {
+		if m.event != ec.label {
+			continue
+		}
+		msg, err := view.EmtecDashboard(m.lang, c.signer, ec.label,
+			c.withPendingSceneSwitches(tracks, m.channelId, m.messageTs), "", time.Now())
+		if err != nil {
+			logger.Warn(fmt.Sprintf("failed to render dashboard: %v", err))
+			return
+		}
+		if err := sc.UpdateMessage(ctx, m.channelId, m.messageTs, msg); err != nil {
+			logger.Warn(fmt.Sprintf("failed to refresh dashboard (channel: %s, messageTs: %s): %v",
+				m.channelId, m.messageTs, err))
+		}
+	}
+	if notificationChannel != "" {
+		msg, err := view.EmtecChanges(lang, ec.label, changes)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("failed to render changes: %v", err))
+			return
+		}
+		if err := sc.PostMessage(ctx, notificationChannel, msg); err != nil {
+			logger.Warn(fmt.Sprintf("failed to post changes: %v", err))
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+
+	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/internal/tracing"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+)
+
+// WithSceneSwitch sets the tracks (names or IDs, "*" for all) which show the
+// confirm dialog on switching the scene, and the undo window.
+//
+// emtec-ecu cannot move the scene back, so if undoWindow is positive, the
+// scene is switched after undoWindow unless it is undone.
+func (c *EmtecController) WithSceneSwitch(confirmTracks []string, undoWindow time.Duration) *EmtecController {
+	c.confirmTracks = confirmTracks
+	c.undoWindow = undoWindow
+	return c
+}
+
+func (c *EmtecController) needsConfirmation(track *pb.Track) bool {
+	for _, t := range c.confirmTracks {
+		if t == "*" || t == strconv.Itoa(int(track.TrackId)) ||
+			normalizeTrackName(t) == normalizeTrackName(track.TrackName) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingSceneSwitch is switching the scene which can be undone
+type pendingSceneSwitch struct {
+	timer *time.Timer
+	// restore restores the message before clicked
+	restore func(context.Context) error
+	// target is the target of audit log
+	target string
+	// sc, channelId, messageTs, userId and lang are used to report the result to the thread
+	sc        infra_slack.SlackClient
+	channelId string
+	messageTs string
+	userId    string
+	lang      i18n.Lang
+}
+
+// moveSceneToNext switches the scene unless it has already been switched
+// since value was rendered. It returns false if the switch is ignored.
+// The result is recorded to audit log as the action of userId.
+func (c *EmtecController) moveSceneToNext(ctx context.Context, ec emtecClient, value api.SceneNext, userId string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "EmtecController.moveSceneToNext", trace.WithAttributes(
+		attribute.String("emtec.target", auditTarget(ec.label, value.Name)),
+	))
+	switched, err := c.lockedMoveSceneToNext(ctx, ec, value)
+	span.SetAttributes(attribute.Bool("emtec.switched", switched))
+	tracing.End(span, err)
+	entry := auditEntry(userId, "emtec.next-scene", auditTarget(ec.label, value.Name), err)
+	if err == nil && !switched {
+		entry.Outcome = audit.OutcomeIgnored
+		entry.Detail = "already switched"
+	}
+	c.audit.Record(ctx, entry)
+	return switched, err
+}
+
+func (c *EmtecController) lockedMoveSceneToNext(ctx context.Context, ec emtecClient, value api.SceneNext) (bool, error) {
+	c.sceneMu.Lock()
+	defer c.sceneMu.Unlock()
+	if value.SceneIndex >= 0 {
+		resp, err := ec.scene.ListScene(ctx, &pb.ListSceneRequest{TrackId: value.Id})
+		if err != nil {
+			return false, xerrors.Errorf("cndSceneClient.ListScene failed: %w", emtecError(err))
+		}
+		if view.CurrentSceneIndex(resp.Scene) != value.SceneIndex {
+			return false, nil
+		}
+	}
+	if _, err := ec.scene.MoveSceneToNext(ctx, &pb.MoveSceneToNextRequest{TrackId: value.Id}); err != nil {
+		return false, xerrors.Errorf("cndSceneClient.MoveSceneToNext failed: %w", emtecError(err))
+	}
+	return true, nil
+}
+
+func (c *EmtecController) UpdateSceneToNext(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+	sentUserId := interaction.User.ID
+	lang := i18n.FromContext(ctx)
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	v, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	value, err := api.NewSceneNext(v)
+	if err != nil {
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+	ec, err := c.eventOf(channelId, value.Event)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	if c.undoWindow <= 0 {
+		return c.commitSceneSwitch(ctx, sc, ec, value, channelId, messageTs, interaction.Message.Msg, sentUserId)
+	}
+
+	original, err := json.Marshal(interaction.Message.Msg)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal message: %w", err)
+	}
+	msg, err := view.EmtecSceneSwitchPending(lang, c.signer, interaction.Message.Msg, value, c.undoWindow)
+	if err != nil {
+		return xerrors.Errorf("invalid interactive message: %w", err)
+	}
+
+	p := &pendingSceneSwitch{
+		restore: func(ctx context.Context) error {
+			var restored slack.Msg
+			if err := json.Unmarshal(original, &restored); err != nil {
+				return xerrors.Errorf("failed to unmarshal message: %w", err)
+			}
+			if err := sc.UpdateMessage(ctx, channelId, messageTs, restored); err != nil {
+				return xerrors.Errorf("failed to post message: %w", err)
+			}
+			return nil
+		},
+		target:    auditTarget(ec.label, value.Name),
+		sc:        sc,
+		channelId: channelId,
+		messageTs: messageTs,
+		userId:    sentUserId,
+		lang:      lang,
+	}
+	if !c.startSceneSwitch(ctx, pendingKey(channelId, messageTs, value.Id), p, func(ctx context.Context) error {
+		return c.commitSceneSwitch(ctx, sc, ec, value, channelId, messageTs, msg, sentUserId)
+	}) {
+		// double click
+		return nil
+	}
+
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+// pendingKey returns the key of the pending switch of the track in the message
+func pendingKey(channelId, messageTs string, trackId int32) string {
+	return fmt.Sprintf("%s/%s/%d", channelId, messageTs, trackId)
+}
+
+// startSceneSwitch registers p as pending and calls commit after the undo window
+// unless it is undone. It returns false if the switch of key is already pending.
+func (c *EmtecController) startSceneSwitch(ctx context.Context,
+	key string, p *pendingSceneSwitch, commit func(context.Context) error,
+) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if _, ok := c.pending[key]; ok {
+		return false
+	}
+	ctx = context.WithoutCancel(ctx)
+	c.pendingWg.Add(1)
+	p.timer = time.AfterFunc(c.undoWindow, func() {
+		defer c.pendingWg.Done()
+		if _, ok := c.popPendingSceneSwitch(key); !ok {
+			return // undone
+		}
+		if err := commit(ctx); err != nil {
+			log.FromContext(ctx).Error(err.Error(), log.KeyDetail, err)
+			// the error handler of lacks is not called because the handler has already returned
+			if errMsg, err := view.Error(p.lang, p.messageTs, err); err == nil {
+				_ = p.sc.PostMessageToThread(ctx, p.channelId, p.messageTs, errMsg)
+			}
+		}
+	})
+	c.pending[key] = p
+	return true
+}
+
+// isPendingSceneSwitch returns true if the switch of key is in the undo window
+func (c *EmtecController) isPendingSceneSwitch(key string) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	_, ok := c.pending[key]
+	return ok
+}
+
+func (c *EmtecController) popPendingSceneSwitch(key string) (*pendingSceneSwitch, bool) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	p, ok := c.pending[key]
+	delete(c.pending, key)
+	return p, ok
+}
+
+// commitSceneSwitch switches the scene and replaces the button of msg with "Switched"
+func (c *EmtecController) commitSceneSwitch(ctx context.Context,
+	sc infra_slack.SlackClient, ec emtecClient, value api.SceneNext,
+	channelId, messageTs string, msg slack.Msg, sentUserId string,
+) error {
+	switched, err := c.moveSceneToNext(ctx, ec, value, sentUserId)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	lang := i18n.FromContext(ctx)
+	if !switched {
+		note, err := view.EmtecSceneSwitchIgnored(lang, value.Name, sentUserId)
+		if err != nil {
+			return xerrors.Errorf("failed to render message: %w", err)
+		}
+		if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+			return xerrors.Errorf("failed to post message: %w", err)
+		}
+		return nil
+	}
+
+	msg, err = view.EmtecMovedToNextScene(lang, msg)
+	if err != nil {
+		log.FromContext(ctx).Debug(fmt.Sprintf("invalid interactive message: %v", err))
+		return nil
+	}
+
+	if err := sc.UpdateMessage(
+		ctx, channelId, messageTs, msg,
+	); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+
+	note, err := view.EmtecSwitchPushed(lang, sentUserId)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+// UndoSceneSwitch cancels the pending switch and restores the message
+func (c *EmtecController) UndoSceneSwitch(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	v, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	value, err := api.NewSceneNext(v)
+	if err != nil {
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+
+	lang := i18n.FromContext(ctx)
+	p, ok := c.popPendingSceneSwitch(pendingKey(channelId, messageTs, value.Id))
+	if !ok {
+		note, err := view.EmtecSceneSwitchTooLateToUndo(lang, interaction.User.ID)
+		if err != nil {
+			return xerrors.Errorf("failed to render message: %w", err)
+		}
+		if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+			return xerrors.Errorf("failed to post message: %w", err)
+		}
+		return nil
+	}
+	if p.timer.Stop() {
+		c.pendingWg.Done()
+	}
+	c.audit.Record(ctx, auditEntry(interaction.User.ID, "emtec.undo-scene", p.target, nil))
+
+	if err := p.restore(ctx); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	note, err := view.EmtecSceneSwitchUndone(lang, interaction.User.ID)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+// DrainPendingSceneSwitches cancels the switches in the undo window, restores their
+// messages and reports it to the threads, then waits for the switches being committed.
+// It is called on shutdown, when the timers of the switches would be lost.
+func (c *EmtecController) DrainPendingSceneSwitches(ctx context.Context) error {
+	c.pendingMu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]*pendingSceneSwitch)
+	c.pendingMu.Unlock()
+
+	for _, p := range pending {
+		if p.timer.Stop() {
+			c.pendingWg.Done()
+		}
+		if err := c.cancelSceneSwitch(ctx, p); err != nil {
+			c.log.Error(err.Error(), log.KeyDetail, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.pendingWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return xerrors.Errorf("switching scenes is interrupted: %w", ctx.Err())
+	}
+}
+
+func (c *EmtecController) cancelSceneSwitch(ctx context.Context, p *pendingSceneSwitch) error {
+	entry := auditEntry(p.userId, "emtec.next-scene", p.target, nil)
+	entry.Outcome = audit.OutcomeIgnored
+	entry.Detail = "cancelled on shutdown"
+	c.audit.Record(ctx, entry)
+
+	if err := p.restore(ctx); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	note, err := view.EmtecSceneSwitchCanceledOnShutdown(p.lang, p.userId)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := p.sc.PostMessageToThread(ctx, p.channelId, p.messageTs, note); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/xerrors"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+// EmtecTimetableEntry is a schedule defined in the config file
+type EmtecTimetableEntry struct {
+	// Event is the event name (optional if only one event is configured)
+	Event string
+	// Track is the ID or the name of the track
+	Track   string
+	Enabled bool
+	At      time.Time
+	// ChannelId is the channel to which the execution is reported
+	ChannelId string
+	// Lang is the language of the report
+	Lang i18n.Lang
+}
+
+// WithTimeZone sets the time zone in which "HH:MM" of schedules is read and times are shown
+func (c *EmtecController) WithTimeZone(location *time.Location) *EmtecController {
+	c.location = location
+	return c
+}
+
+// emtecSchedule is a pending toggle of automation
+type emtecSchedule struct {
+	id int
+	// event is the event name
+	event string
+	// track is the ID or the name of the track, which is resolved on execution
+	track   string
+	enabled bool
+	// at is in the time zone of EmtecController
+	at        time.Time
+	channelId string
+	createdBy string
+	// lang is the language of the report of the execution
+	lang i18n.Lang
+}
+
+func (s emtecSchedule) view(label string) view.EmtecScheduleItem {
+	return view.EmtecScheduleItem{
+		Id: s.id, Event: label, Track: s.track, Enabled: s.enabled, At: s.at, CreatedBy: s.createdBy,
+	}
+}
+
+// emtecScheduler keeps pending schedules in memory
+type emtecScheduler struct {
+	mu        sync.Mutex
+	nextId    int
+	schedules map[int]emtecSchedule
+}
+
+func newEmtecScheduler() *emtecScheduler {
+	return &emtecScheduler{nextId: 1, schedules: make(map[int]emtecSchedule)}
+}
+
+func (s *emtecScheduler) add(schedule emtecSchedule) emtecSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule.id = s.nextId
+	s.nextId++
+	s.schedules[schedule.id] = schedule
+	return schedule
+}
+
+func (s *emtecScheduler) cancel(id int) (emtecSchedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, ok := s.schedules[id]
+	delete(s.schedules, id)
+	return schedule, ok
+}
+
+// list returns pending schedules ordered by time
+func (s *emtecScheduler) list() []emtecSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []emtecSchedule
+	for _, schedule := range s.schedules {
+		result = append(result, schedule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].at.Equal(result[j].at) {
+			return result[i].id < result[j].id
+		}
+		return result[i].at.Before(result[j].at)
+	})
+	return result
+}
+
+// popDue removes and returns schedules whose time has come
+func (s *emtecScheduler) popDue(now time.Time) []emtecSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []emtecSchedule
+	for id, schedule := range s.schedules {
+		if !schedule.at.After(now) {
+			result = append(result, schedule)
+			delete(s.schedules, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result
+}
+
+// RunScheduler registers timetable and executes schedules until ctx is done.
+// Past entries of timetable are ignored. If the event or the track of any entry
+// cannot be resolved, no entry is registered and the invalid entries are reported
+// to the channels of timetable.
+func (c *EmtecController) RunScheduler(ctx context.Context, client slack.Client, timetable []EmtecTimetableEntry) {
+	logger := c.log.With("component", "emtecScheduler")
+	ctx = log.IntoContext(ctx, logger)
+
+	now := time.Now()
+	var schedules []emtecSchedule
+	for i, entry := range timetable {
+		if !entry.At.After(now) {
+			continue
+		}
+		// id is the position in timetable until registered
+		schedules = append(schedules, emtecSchedule{
+			id: i + 1, event: entry.Event, track: entry.Track, enabled: entry.Enabled,
+			at: entry.At.In(c.location), channelId: entry.ChannelId, createdBy: "timetable", lang: entry.Lang,
+		})
+	}
+	if invalid := c.invalidSchedules(ctx, schedules); len(invalid) != 0 {
+		logger.Error(fmt.Sprintf("timetable is rejected because of %d invalid entries", len(invalid)))
+		c.rejectTimetable(ctx, client, timetable, invalid)
+		schedules = nil
+	}
+	for _, schedule := range schedules {
+		c.scheduler.add(schedule)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, schedule := range c.scheduler.popDue(now) {
+				c.executeSchedule(ctx, client, schedule)
+			}
+		}
+	}
+}
+
+// invalidSchedules returns the schedules whose event or track cannot be resolved.
+// Tracks are not checked if EMTEC of the event is unavailable, which are checked on execution.
+func (c *EmtecController) invalidSchedules(ctx context.Context, schedules []emtecSchedule) []view.EmtecInvalidSchedule {
+	logger := log.FromContext(ctx)
+	var invalid []view.EmtecInvalidSchedule
+	for _, schedule := range schedules {
+		label := schedule.event
+		ec, err := c.resolveEvent(schedule.channelId, schedule.event)
+		if err == nil {
+			label = ec.label
+			_, err = trackIdOf(ctx, ec, strings.Fields(schedule.track), "")
+			if kind := api.ErrorOf(err).Kind; err != nil &&
+				kind != api.ErrorKindInvalidInput && kind != api.ErrorKindNotFound {
+				logger.Warn(fmt.Sprintf("track of timetable #%d is not checked: %v", schedule.id, err))
+				err = nil
+			}
+		}
+		if err != nil {
+			invalid = append(invalid, view.EmtecInvalidSchedule{Item: schedule.view(label), Err: err})
+		}
+	}
+	return invalid
+}
+
+// rejectTimetable reports the invalid entries to each channel of timetable
+func (c *EmtecController) rejectTimetable(ctx context.Context,
+	client slack.Client, timetable []EmtecTimetableEntry, invalid []view.EmtecInvalidSchedule,
+) {
+	logger := log.FromContext(ctx)
+	sc, err := c.slackFactory.New(client)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initialize Slack client: %v", err))
+		return
+	}
+	reported := make(map[string]bool)
+	for _, entry := range timetable {
+		if reported[entry.ChannelId] {
+			continue
+		}
+		reported[entry.ChannelId] = true
+		msg, err := view.EmtecTimetableRejected(entry.Lang, invalid)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("failed to render message: %v", err))
+			return
+		}
+		if err := sc.PostMessage(ctx, entry.ChannelId, msg); err != nil {
+			logger.Warn(fmt.Sprintf("failed to post message: %v", err))
+		}
+	}
+}
+
+func (c *EmtecController) executeSchedule(ctx context.Context, client slack.Client, schedule emtecSchedule) {
+	logger := log.FromContext(ctx).With("scheduleId", schedule.id)
+	sc, err := c.slackFactory.New(client)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initialize Slack client: %v", err))
+		return
+	}
+
+	label, err := c.runSchedule(ctx, schedule)
+	if err == nil {
+		logger.Info("executed schedule")
+	} else {
+		logger.Warn(fmt.Sprintf("failed to execute schedule: %v", err))
+	}
+	entry := audit.Entry{
+		Source: audit.SourceSlack, Actor: schedule.createdBy, Action: automationAction(schedule.enabled),
+		Target: auditTarget(label, schedule.track), Outcome: audit.OutcomeSuccess,
+		Detail: fmt.Sprintf("schedule #%d", schedule.id),
+	}
+	if schedule.createdBy == "timetable" {
+		entry.Source = audit.SourceSystem
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Detail += ": " + view.ErrorText(i18n.English, err)
+	}
+	c.audit.Record(ctx, entry)
+
+	msg, err := view.EmtecScheduleExecuted(schedule.lang, schedule.view(label), err)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to render message: %v", err))
+		return
+	}
+	if err := sc.PostMessage(ctx, schedule.channelId, msg); err != nil {
+		logger.Warn(fmt.Sprintf("failed to post message: %v", err))
+	}
+}
+
+// runSchedule switches automation and returns the label of the event and the
+// error, whose message is shown as the reason of failure
+func (c *EmtecController) runSchedule(ctx context.Context, schedule emtecSchedule) (string, error) {
+	ec, err := c.resolveEvent(schedule.channelId, schedule.event)
+	if err != nil {
+		return "", err
+	}
+	if available, state := ec.availability.Available(); !available {
+		return ec.label, api.Unavailable(api.MsgEmtecUnavailableState, nil, state)
+	}
+	trackId, err := trackIdOf(ctx, ec, strings.Fields(schedule.track), "")
+	if err != nil {
+		return ec.label, xerrors.Errorf("failed to look up track: %w", err)
+	}
+	if _, err := c.setAutomation(ctx, ec, trackId, schedule.enabled); err != nil {
+		return ec.label, xerrors.Errorf("failed to switch automation: %w", err)
+	}
+	return ec.label, nil
+}
+
+func (c *EmtecController) ScheduleEnableAutomation(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	return c.schedule(ctx, ev, client, true)
+}
+
+func (c *EmtecController) ScheduleDisableAutomation(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	return c.schedule(ctx, ev, client, false)
+}
+
+// schedule handles "@seaman emtec schedule enable-track <track> at <time>"
+func (c *EmtecController) schedule(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client, enabled bool) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	// parse arguments: args[0] is the subcommand of schedule
+	args, eventName := emtecArgs(ev.Text)
+	usage := fmt.Sprintf("emtec schedule %s <track ID or name> at <HH:MM>", automationCommand(enabled))
+	at, trackArgs, err := scheduleTimeFromArgs(args[1:], time.Now().In(c.location), usage)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	ec, err := c.eventOf(channelId, eventName)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	// validate the track now, but it is resolved again on execution
+	if _, err := trackIdOf(ctx, ec, trackArgs, usage); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	schedule := c.scheduler.add(emtecSchedule{
+		event: ec.name, track: strings.Join(trackArgs, " "), enabled: enabled,
+		at: at, channelId: channelId, createdBy: ev.User, lang: i18n.FromContext(ctx),
+	})
+	entry := auditEntry(ev.User, "emtec.schedule", auditTarget(ec.label, schedule.track), nil)
+	entry.Detail = fmt.Sprintf("#%d %s at %s", schedule.id, automationAction(enabled), at.Format(time.RFC3339))
+	c.audit.Record(ctx, entry)
+	msg, err := view.EmtecScheduled(i18n.FromContext(ctx), schedule.view(ec.label))
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+func (c *EmtecController) ListSchedule(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	var items []view.EmtecScheduleItem
+	for _, schedule := range c.scheduler.list() {
+		items = append(items, schedule.view(c.events[schedule.event].label))
+	}
+	msg, err := view.EmtecListSchedule(i18n.FromContext(ctx), items)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+func (c *EmtecController) CancelSchedule(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	// parse arguments: args[0] is the subcommand of schedule
+	args, _ := emtecArgs(ev.Text)
+	const usage = "emtec schedule cancel <schedule ID>"
+	if len(args) != 2 {
+		return api.InvalidInput(api.MsgEmtecScheduleIdRequired, usage)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil {
+		return api.InvalidInput(api.MsgEmtecScheduleIdInvalid, args[1], usage)
+	}
+	schedule, ok := c.scheduler.cancel(id)
+	if !ok {
+		return api.NotFound(api.MsgEmtecScheduleNotFound, nil, id)
+	}
+	entry := auditEntry(ev.User, "emtec.cancel-schedule", auditTarget(c.events[schedule.event].label, schedule.track), nil)
+	entry.Detail = fmt.Sprintf("#%d", schedule.id)
+	c.audit.Record(ctx, entry)
+
+	msg, err := view.EmtecScheduleCanceled(i18n.FromContext(ctx), schedule.view(c.events[schedule.event].label), ev.User)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+// scheduleTimeFromArgs parses "<track...> at <time>", where time is "15:04"
+// (today or tomorrow in the location of now) or RFC3339.
+// It returns the time and args of the track, or the error of InvalidInput.
+func scheduleTimeFromArgs(args []string, now time.Time, usage string) (time.Time, []string, error) {
+	if len(args) < 3 || !strings.EqualFold(args[len(args)-2], "at") {
+		return time.Time{}, nil, api.InvalidInput(api.MsgEmtecScheduleUsage, usage)
+	}
+	trackArgs, value := args[:len(args)-2], args[len(args)-1]
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		if !at.After(now) {
+			return time.Time{}, nil, api.InvalidInput(api.MsgEmtecScheduleTimePast, value)
+		}
+		return at.In(now.Location()), trackArgs, nil
+	}
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, nil, api.InvalidInput(api.MsgEmtecScheduleTimeInvalid, value)
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, trackArgs, nil
+}
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+)
+
+// trackIdOf returns the track ID specified by args, which is either the ID or
+// the name of the track. If args is empty, it returns the error of InvalidInput
+// with usage, and if no track matches, it returns the error of NotFound.
+func trackIdOf(ctx context.Context, ec emtecClient, args []string, usage string) (int32, error) {
+	if len(args) == 0 {
+		return 0, api.InvalidInput(api.MsgEmtecTrackRequired, usage)
+	}
+
+	resp, err := ec.track.ListTrack(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, xerrors.Errorf("cndTrackClient.ListTrack failed: %w", emtecError(err))
+	}
+	query := strings.Join(args, " ")
+	if trackId, err := strconv.Atoi(query); err == nil {
+		for _, track := range resp.Tracks {
+			if track.TrackId == int32(trackId) {
+				return track.TrackId, nil
+			}
+		}
+	}
+	switch matched := matchTracks(resp.Tracks, query); len(matched) {
+	case 1:
+		return matched[0].TrackId, nil
+	case 0:
+		return 0, api.NotFound(api.MsgEmtecTrackNotFound, nil, query)
+	default:
+		var names []string
+		for _, track := range matched {
+			names = append(names, track.TrackName)
+		}
+		return 0, api.InvalidInput(api.MsgEmtecTrackAmbiguous, query, strings.Join(names, ", "))
+	}
+}
+
+// matchTracks returns tracks whose name matches query. Names are compared
+// ignoring case and symbols, and exact matches take precedence over prefix
+// matches, which take precedence over partial matches.
+func matchTracks(tracks []*pb.Track, query string) []*pb.Track {
+	q := normalizeTrackName(query)
+	if q == "" {
+		return nil
+	}
+	for _, match := range []func(name string) bool{
+		func(name string) bool { return name == q },
+		func(name string) bool { return strings.HasPrefix(name, q) },
+		func(name string) bool { return strings.Contains(name, q) },
+	} {
+		var matched []*pb.Track
+		for _, track := range tracks {
+			if match(normalizeTrackName(track.TrackName)) {
+				matched = append(matched, track)
+			}
+		}
+		if len(matched) != 0 {
+			return matched
+		}
+	}
+	return nil
+}
+
+func normalizeTrackName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// automationAction returns the action of audit log for switching automation
+func automationAction(enabled bool) string {
+	return "emtec." + automationCommand(enabled)
+}
+
+// automationCommand returns the subcommand of emtec for switching automation
+func automationCommand(enabled bool) string {
+	if enabled {
+		return "enable-track"
+	}
+	return "disable-track"
+}
+
+func (c *EmtecController) setAutomation(ctx context.Context, ec emtecClient, trackId int32, enabled bool) (*pb.Track, error) {
+	if enabled {
+		track, err := ec.track.EnableAutomation(ctx, &pb.SwitchAutomationRequest{TrackId: trackId})
+		if err != nil {
+			return nil, xerrors.Errorf("cndTrackClient.EnableAutomation failed: %w", emtecError(err))
+		}
+		return track, nil
+	}
+	track, err := ec.track.DisableAutomation(ctx, &pb.SwitchAutomationRequest{TrackId: trackId})
+	if err != nil {
+		return nil, xerrors.Errorf("cndTrackClient.DisableAutomation failed: %w", emtecError(err))
+	}
+	return track, nil
+}
+
+func (c *EmtecController) SelectedTrackToEnable(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.selectedTrack(ctx, interaction, client, true)
+}
+
+func (c *EmtecController) SelectedTrackToDisable(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.selectedTrack(ctx, interaction, client, false)
+}
+
+func (c *EmtecController) selectedTrack(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client, enabled bool) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	value, err := c.signer.Verify(utils.GetCallbackValueOnStaticSelect(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	track, err := api.NewTrack(value)
+	if err != nil {
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+	ec, err := c.eventOf(channelId, track.Event)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	resp, err := c.setAutomation(ctx, ec, track.Id, enabled)
+	c.audit.Record(ctx, auditEntry(interaction.User.ID, automationAction(enabled), auditTarget(ec.label, track.Name), err))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	render := view.EmtecDisabled
+	if enabled {
+		render = view.EmtecEnabled
+	}
+	msg, err := render(i18n.FromContext(ctx), resp.TrackName)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
+
+func (c *EmtecController) SwitchAllToEnable(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.switchAll(ctx, interaction, client, true)
+}
+
+func (c *EmtecController) SwitchAllToDisable(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	return c.switchAll(ctx, interaction, client, false)
+}
+
+// switchAll switches automation of all tracks, continuing even if some of them fail
+func (c *EmtecController) switchAll(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client, enabled bool) error {
+	logger := log.FromContext(ctx)
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	event, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	ec, err := c.eventOf(channelId, event)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	resp, err := ec.track.ListTrack(ctx, &emptypb.Empty{})
+	if err != nil {
+		return xerrors.Errorf("cndTrackClient.ListTrack failed: %w", emtecError(err))
+	}
+	var succeeded, failed []string
+	for _, track := range resp.Tracks {
+		if _, err := c.setAutomation(ctx, ec, track.TrackId, enabled); err != nil {
+			logger.Warn(fmt.Sprintf("failed to switch automation of Track %s: %v", track.TrackName, err))
+			failed = append(failed, track.TrackName)
+			continue
+		}
+		succeeded = append(succeeded, track.TrackName)
+	}
+	entry := auditEntry(interaction.User.ID, automationAction(enabled), auditTarget(ec.label, "all"), nil)
+	if len(failed) != 0 {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Detail = fmt.Sprintf("failed: %s", strings.Join(failed, ", "))
+	}
+	c.audit.Record(ctx, entry)
+
+	msg, err := view.EmtecSwitchedAll(i18n.FromContext(ctx), enabled, succeeded, failed)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	note, err := view.EmtecSwitchPushed(i18n.FromContext(ctx), interaction.User.ID)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessageToThread(ctx, channelId, messageTs, note); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack/socketmode"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/lacks"
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+// userLocaleTTL is how long the locale of a user is cached
+const userLocaleTTL = time.Hour
+
+// LanguagePolicy decides the language of messages
+type LanguagePolicy struct {
+	// Default is used if neither Channels nor UserLocale decides the language
+	Default i18n.Lang
+	// Channels maps channel IDs to their languages, which take precedence over UserLocale
+	Channels map[string]i18n.Lang
+	// UserLocale uses the locale of the Slack user if it is supported
+	UserLocale bool
+}
+
+// LanguageResolver decides the language of each event by LanguagePolicy
+type LanguageResolver struct {
+	policy LanguagePolicy
+
+	mu      sync.Mutex
+	locales map[string]userLocale
+}
+
+type userLocale struct {
+	lang      i18n.Lang
+	expiresAt time.Time
+}
+
+func NewLanguageResolver(policy LanguagePolicy) *LanguageResolver {
+	if policy.Default == "" {
+		policy.Default = i18n.Default
+	}
+	return &LanguageResolver{policy: policy, locales: make(map[string]userLocale)}
+}
+
+// ForChannel returns the language of messages posted to the channel without any user (e.g. schedules)
+func (r *LanguageResolver) ForChannel(channelId string) i18n.Lang {
+	if lang, ok := r.policy.Channels[channelId]; ok {
+		return lang
+	}
+	return r.policy.Default
+}
+
+// IntoContext stores the language of evt into ctx (see i18n.FromContext).
+// It is used as lacks.ContextFunc.
+func (r *LanguageResolver) IntoContext(ctx context.Context, client *socketmode.Client, evt lacks.Event) context.Context {
+	return i18n.IntoContext(ctx, r.resolve(ctx, client, evt))
+}
+
+func (r *LanguageResolver) resolve(ctx context.Context, client *socketmode.Client, evt lacks.Event) i18n.Lang {
+	if lang, ok := r.policy.Channels[evt.ChannelId]; ok {
+		return lang
+	}
+	if r.policy.UserLocale && evt.UserId != "" {
+		if lang := r.userLang(ctx, client, evt.UserId); lang != "" {
+			return lang
+		}
+	}
+	return r.policy.Default
+}
+
+// userLang returns the language of the locale of the user, or empty if it is not supported
+func (r *LanguageResolver) userLang(ctx context.Context, client *socketmode.Client, userId string) i18n.Lang {
+	r.mu.Lock()
+	cached, ok := r.locales[userId]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.lang
+	}
+
+	user, err := client.GetUserInfoContext(ctx, userId)
+	if err != nil {
+		// fall back to the default without caching, so that it is retried on the next event
+		log.FromContext(ctx).Warn(fmt.Sprintf("failed to get the locale of the user: %v", err))
+		return ""
+	}
+	lang, _ := i18n.Parse(user.Locale)
+	r.mu.Lock()
+	r.locales[userId] = userLocale{lang: lang, expiresAt: time.Now().Add(userLocaleTTL)}
+	r.mu.Unlock()
+	return lang
+}
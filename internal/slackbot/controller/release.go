@@ -3,10 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 	"golang.org/x/xerrors"
 
@@ -16,7 +19,10 @@ import (
 	"github.com/cloudnativedaysjp/seaman/internal/service"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
-	"github.com/cloudnativedaysjp/seaman/pkg/log"
+	"github.com/cloudnativedaysjp/seaman/internal/tracing"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+	"github.com/cloudnativedaysjp/seaman/pkg/lacks"
 	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 )
 
@@ -28,25 +34,67 @@ type Target struct {
 type ReleaseController struct {
 	slackFactory infra_slack.SlackClientFactory
 	service      service.GitHubIface
+	audit        *audit.Logger
+	signer       *api.Signer
 	log          *slog.Logger
 
-	targets []Target
+	targets  []Target
+	releases releases
+}
+
+// releases remembers the PullRequests created from each message, so that
+// double submits of the same message do not create duplicated PullRequests.
+type releases struct {
+	mu     sync.Mutex
+	prNums map[string]int
+}
+
+// begin returns the number of the PullRequest for key and true if it has been
+// created or is being created (the number is 0 in the latter case).
+func (r *releases) begin(key string) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prNum, ok := r.prNums[key]; ok {
+		return prNum, true
+	}
+	r.prNums[key] = 0
+	return 0, false
+}
+
+// end records prNum for key. If prNum is 0 (failed), key is forgotten so that it can be retried.
+func (r *releases) end(key string, prNum int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prNum == 0 {
+		delete(r.prNums, key)
+		return
+	}
+	r.prNums[key] = prNum
 }
 
 func NewReleaseController(
 	logger *slog.Logger,
 	slackFactory infra_slack.SlackClientFactory,
+	auditor *audit.Logger,
+	signer *api.Signer,
 	gitcommand gitcommand.GitCommandClient,
 	githubapi githubapi.GitHubApiClient,
 	targets []Target,
 ) *ReleaseController {
 	service := service.NewGitHubService(gitcommand, githubapi)
-	return &ReleaseController{slackFactory, service, logger, targets}
+	return &ReleaseController{
+		slackFactory: slackFactory,
+		service:      service,
+		audit:        auditor,
+		signer:       signer,
+		log:          logger,
+		targets:      targets,
+		releases:     releases{prNums: map[string]int{}},
+	}
 }
 
 func (c *ReleaseController) SelectRepository(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
 	channelId := ev.Channel
-	messageTs := ev.TimeStamp
 	// new client from factory
 	sc, err := c.slackFactory.New(client.Client)
 	if err != nil {
@@ -58,10 +106,11 @@ func (c *ReleaseController) SelectRepository(ctx context.Context, ev *slackevent
 		targetUrls = append(targetUrls, target.Url)
 	}
 
-	if err := sc.PostMessage(ctx, channelId,
-		view.ReleaseListRepo(targetUrls),
-	); err != nil {
-		_ = sc.PostMessage(ctx, channelId, view.SomethingIsWrong(messageTs))
+	msg, err := view.ReleaseListRepo(i18n.FromContext(ctx), c.signer, targetUrls)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
@@ -69,7 +118,6 @@ func (c *ReleaseController) SelectRepository(ctx context.Context, ev *slackevent
 
 //nolint:dupl
 func (c *ReleaseController) SelectReleaseLevel(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
-	logger := log.FromContext(ctx)
 	channelId := interaction.Container.ChannelID
 	messageTs := interaction.Container.MessageTs
 
@@ -79,14 +127,22 @@ func (c *ReleaseController) SelectReleaseLevel(ctx context.Context, interaction
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	orgRepo, err := api.NewOrgRepo(utils.GetCallbackValueOnStaticSelect(interaction))
+	value, err := c.signer.Verify(utils.GetCallbackValueOnStaticSelect(interaction))
 	if err != nil {
-		logger.Debug(fmt.Sprintf("invalid callback value: %v", err))
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return nil
+		return xerrors.Errorf("%w", err)
 	}
-	if err := sc.UpdateMessage(ctx, channelId, messageTs, view.ReleaseListLevel(orgRepo)); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
+	orgRepo, err := api.NewOrgRepo(value)
+	if err != nil {
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+	if _, err := c.target(orgRepo); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	msg, err := view.ReleaseListLevel(i18n.FromContext(ctx), c.signer, orgRepo)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
@@ -94,7 +150,6 @@ func (c *ReleaseController) SelectReleaseLevel(ctx context.Context, interaction
 
 //nolint:dupl
 func (c *ReleaseController) SelectConfirmation(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
-	logger := log.FromContext(ctx)
 	channelId := interaction.Container.ChannelID
 	messageTs := interaction.Container.MessageTs
 	// new client from factory
@@ -103,23 +158,28 @@ func (c *ReleaseController) SelectConfirmation(ctx context.Context, interaction
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	orgRepoLevel, err := api.NewOrgRepoLevel(utils.GetCallbackValueOnButton(interaction))
+	value, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
 	if err != nil {
-		logger.Debug(fmt.Sprintf("invalid callback value: %v", err))
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return nil
+		return xerrors.Errorf("%w", err)
 	}
-	if err := sc.UpdateMessage(
-		ctx, channelId, messageTs, view.ReleaseConfirmation(orgRepoLevel),
-	); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
+	orgRepoLevel, err := api.NewOrgRepoLevel(value)
+	if err != nil {
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+	if _, err := c.target(orgRepoLevel.OrgRepo); err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	msg, err := view.ReleaseConfirmation(i18n.FromContext(ctx), c.signer, orgRepoLevel)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
 }
 
 func (c *ReleaseController) CreatePullRequestForRelease(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
-	logger := log.FromContext(ctx)
 	channelId := interaction.Container.ChannelID
 	messageTs := interaction.Container.MessageTs
 
@@ -129,37 +189,189 @@ func (c *ReleaseController) CreatePullRequestForRelease(ctx context.Context, int
 		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	orgRepoLevel, err := api.NewOrgRepoLevel(utils.GetCallbackValueOnButton(interaction))
+	value, err := c.signer.Verify(utils.GetCallbackValueOnButton(interaction))
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	orgRepoLevel, err := api.NewOrgRepoLevel(value)
 	if err != nil {
-		logger.Debug(fmt.Sprintf("invalid callback value: %v", err))
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return nil
+		return api.InvalidInput(api.MsgInvalidCallbackValue, err)
 	}
+	return c.createPullRequest(ctx, sc, channelId, messageTs, interaction.User.ID, orgRepoLevel, "")
+}
 
-	if err := sc.UpdateMessage(ctx, channelId, messageTs, view.ReleaseProcessing()); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("failed to post message: %w", err)
+// OpenReleaseModal opens the modal instead of the wizard in the message
+func (c *ReleaseController) OpenReleaseModal(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) error {
+	channelId := interaction.Container.ChannelID
+	messageTs := interaction.Container.MessageTs
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
 	}
 
-	var baseBranch string
-	repoUrl := orgRepoLevel.RepositoryUrl()
+	var targetUrls []string
+	for _, target := range c.targets {
+		targetUrls = append(targetUrls, target.Url)
+	}
+	modal, err := view.ReleaseModal(i18n.FromContext(ctx), c.signer, targetUrls,
+		api.ReleaseModalMetadata{ChannelId: channelId, MessageTs: messageTs})
+	if err != nil {
+		return xerrors.Errorf("failed to render view: %w", err)
+	}
+	if err := sc.OpenView(ctx, interaction.TriggerID, modal); err != nil {
+		return xerrors.Errorf("failed to open view: %w", err)
+	}
+	return nil
+}
+
+// SubmitReleaseModal validates the inputs of the modal and shows invalid ones in the modal.
+// If they are valid, it closes the modal, then creates the PullRequest and shows the result
+// in the message from which the modal was opened.
+func (c *ReleaseController) SubmitReleaseModal(ctx context.Context, interaction slack.InteractionCallback, client *socketmode.Client) (*slack.ViewSubmissionResponse, error) {
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+
+	metadata, err := api.NewReleaseModalMetadata(interaction.View.PrivateMetadata)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid private metadata: %w", err)
+	}
+	channelId, messageTs := metadata.ChannelId, metadata.MessageTs
+
+	values := interaction.View.State.Values
+	orgRepo, err := c.orgRepoOfModal(values)
+	if err == nil {
+		_, err = c.target(orgRepo)
+	}
+	if err != nil {
+		return releaseModalError(ctx, api.BlockIdRelease_Repository, err)
+	}
+	level := values[api.BlockIdRelease_Level][api.BlockIdRelease_Level].SelectedOption.Value
+	if !api.IsReleaseLevel(level) {
+		return releaseModalError(ctx, api.BlockIdRelease_Level,
+			api.InvalidInput(api.MsgReleaseInvalidLevel, level))
+	}
+	notes := values[api.BlockIdRelease_Notes][api.BlockIdRelease_Notes].Value
+
+	// the response must be returned within 3 seconds, so the PullRequest is created after that
+	lacks.AfterAck(ctx, func(ctx context.Context) error {
+		return c.createPullRequest(ctx, sc, channelId, messageTs, interaction.User.ID, orgRepo.WithLevel(level), notes)
+	})
+	return nil, nil
+}
+
+// releaseModalError shows the reason of err at the input of blockId in the modal.
+// It returns err as is if the reason cannot be shown to the user.
+func releaseModalError(ctx context.Context, blockId string, err error) (*slack.ViewSubmissionResponse, error) {
+	e := api.ErrorOf(err)
+	if e.Kind == api.ErrorKindInternal || e.Message == "" {
+		return nil, xerrors.Errorf("%w", err)
+	}
+	return view.ReleaseModalError(blockId, fmt.Sprintf("%s: %s", e.Kind, view.ErrorText(i18n.FromContext(ctx), err))), nil
+}
+
+func (c *ReleaseController) orgRepoOfModal(values map[string]map[string]slack.BlockAction) (api.OrgRepo, error) {
+	value, err := c.signer.Verify(
+		values[api.BlockIdRelease_Repository][api.BlockIdRelease_Repository].SelectedOption.Value)
+	if err != nil {
+		return api.OrgRepo{}, xerrors.Errorf("%w", err)
+	}
+	orgRepo, err := api.NewOrgRepo(value)
+	if err != nil {
+		return api.OrgRepo{}, api.InvalidInput(api.MsgInvalidCallbackValue, err)
+	}
+	return orgRepo, nil
+}
+
+// target returns the release target of orgRepo. The callback value may refer to
+// the repository which has been removed from the config since it was rendered.
+func (c *ReleaseController) target(orgRepo api.OrgRepo) (Target, error) {
+	repoUrl := orgRepo.RepositoryUrl()
 	for _, target := range c.targets {
 		if target.Url == repoUrl {
-			baseBranch = target.BaseBranch
-			break
+			return target, nil
 		}
 	}
-	prNum, err := c.service.CreatePullRequestWithEmptyCommit(ctx,
-		orgRepoLevel.Org(), orgRepoLevel.Repo(), orgRepoLevel.Level(), baseBranch, messageTs)
+	return Target{}, api.NotFound(api.MsgReleaseNotTarget, nil, orgRepo.Org(), orgRepo.Repo())
+}
+
+// createPullRequest creates the PullRequest for release and shows its link in the message
+func (c *ReleaseController) createPullRequest(ctx context.Context,
+	sc infra_slack.SlackClient, channelId, messageTs, userId string,
+	orgRepoLevel api.OrgRepoLevel, notes string,
+) (err error) {
+	ctx, span := tracer.Start(ctx, "ReleaseController.createPullRequest", trace.WithAttributes(
+		attribute.String("github.repository", orgRepoLevel.RepositoryUrl()),
+		attribute.String("release.level", orgRepoLevel.Level()),
+	))
+	defer func() { tracing.End(span, err) }()
+	lang := i18n.FromContext(ctx)
+
+	target, err := c.target(orgRepoLevel.OrgRepo)
+	if err == nil && !api.IsReleaseLevel(orgRepoLevel.Level()) {
+		err = api.InvalidInput(api.MsgReleaseInvalidLevel, orgRepoLevel.Level())
+	}
 	if err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
-		return xerrors.Errorf("service.CreatePullRequest failed: %w", err)
+		// replace the confirmation message with the reason, which is also
+		// the only place to report it on the submission of the modal
+		if errMsg, err := view.Error(lang, messageTs, err); err == nil {
+			_ = sc.UpdateMessage(ctx, channelId, messageTs, errMsg)
+		}
+		return api.Reported(err)
 	}
 
-	if err := sc.UpdateMessage(
-		ctx, channelId, messageTs, view.ReleaseDisplayPrLink(orgRepoLevel, prNum),
-	); err != nil {
-		_ = sc.UpdateMessage(ctx, channelId, messageTs, view.SomethingIsWrong(messageTs))
+	key := channelId + "/" + messageTs
+	if prNum, ok := c.releases.begin(key); ok {
+		if prNum == 0 {
+			// the first submit shows the result in the message
+			return nil
+		}
+		return c.showPullRequest(ctx, sc, channelId, messageTs, orgRepoLevel, prNum)
+	}
+	var prNum int
+	defer func() { c.releases.end(key, prNum) }()
+
+	msg, err := view.ReleaseProcessing(lang)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+
+	prNum, err = c.service.CreatePullRequestWithEmptyCommit(ctx,
+		orgRepoLevel.Org(), orgRepoLevel.Repo(), orgRepoLevel.Level(), target.BaseBranch, messageTs, notes)
+	entry := auditEntry(userId, "release.create-pr", target.Url, err)
+	entry.Detail = fmt.Sprintf("level=%s", orgRepoLevel.Level())
+	if err == nil {
+		entry.Detail += fmt.Sprintf(" pr=#%d", prNum)
+	}
+	c.audit.Record(ctx, entry)
+	if err != nil {
+		// replace the processing message with the reason
+		err = gitHubError(err)
+		if errMsg, err := view.Error(lang, messageTs, err); err == nil {
+			_ = sc.UpdateMessage(ctx, channelId, messageTs, errMsg)
+		}
+		return api.Reported(xerrors.Errorf("service.CreatePullRequest failed: %w", err))
+	}
+	return c.showPullRequest(ctx, sc, channelId, messageTs, orgRepoLevel, prNum)
+}
+
+// showPullRequest shows the link of the PullRequest in the message
+func (c *ReleaseController) showPullRequest(ctx context.Context,
+	sc infra_slack.SlackClient, channelId, messageTs string,
+	orgRepoLevel api.OrgRepoLevel, prNum int,
+) error {
+	msg, err := view.ReleaseDisplayPrLink(i18n.FromContext(ctx), orgRepoLevel, prNum)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.UpdateMessage(ctx, channelId, messageTs, msg); err != nil {
 		return xerrors.Errorf("failed to post message: %w", err)
 	}
 	return nil
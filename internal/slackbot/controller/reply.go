@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/pkg/lacks"
+)
+
+// ReplyPolicy decides where the replies to commands are posted
+type ReplyPolicy struct {
+	// Thread makes the replies to all commands go to the thread of the mention
+	Thread bool
+	// ThreadChannels is the list of channel IDs in which the replies go to the thread
+	ThreadChannels []string
+	// ThreadCommands is the list of commands (e.g. "release") or their prefixes
+	// (e.g. "emtec") whose replies go to the thread
+	ThreadCommands []string
+	// Ephemeral makes errors and the help visible only to the requester
+	Ephemeral bool
+}
+
+// threaded reports whether the replies to the command go to the thread of the mention
+func (p ReplyPolicy) threaded(evt lacks.Event) bool {
+	if !evt.IsCommand() {
+		return false
+	}
+	if p.Thread {
+		return true
+	}
+	for _, channelId := range p.ThreadChannels {
+		if channelId == evt.ChannelId {
+			return true
+		}
+	}
+	for _, command := range p.ThreadCommands {
+		if evt.Name == command || strings.HasPrefix(evt.Name, command+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// threadTs returns the timestamp of the thread to which the replies to evt are posted.
+// It is empty if they are posted to the channel.
+func (p ReplyPolicy) threadTs(evt lacks.Event) string {
+	if p.threaded(evt) {
+		return evt.ThreadRoot()
+	}
+	if evt.IsCommand() {
+		return ""
+	}
+	// interactions are replied in the thread where the message is
+	return evt.ThreadTs
+}
+
+// NewReplySlackClientFactory returns SlackClientFactory whose clients post the replies
+// to commands in the thread of the mention if policy requires it
+func NewReplySlackClientFactory(factory infra_slack.SlackClientFactory, policy ReplyPolicy) infra_slack.SlackClientFactory {
+	return &replySlackClientFactory{factory, policy}
+}
+
+type replySlackClientFactory struct {
+	factory infra_slack.SlackClientFactory
+	policy  ReplyPolicy
+}
+
+func (f replySlackClientFactory) New(client slack.Client) (infra_slack.SlackClient, error) {
+	sc, err := f.factory.New(client)
+	if err != nil {
+		return nil, err
+	}
+	return &replySlackClient{sc, f.policy}, nil
+}
+
+// replySlackClient redirects PostMessage of the replies to commands to the thread.
+// PostMessageAndGetTs is not redirected because its message (e.g. the dashboard) is kept in the channel.
+type replySlackClient struct {
+	infra_slack.SlackClient
+	policy ReplyPolicy
+}
+
+func (s *replySlackClient) PostMessage(ctx context.Context, channel string, msg slack.Msg) error {
+	if evt, ok := lacks.EventFromContext(ctx); ok && evt.ChannelId == channel && s.policy.threaded(evt) {
+		return s.SlackClient.PostMessageToThread(ctx, channel, evt.ThreadRoot(), msg)
+	}
+	return s.SlackClient.PostMessage(ctx, channel, msg)
+}
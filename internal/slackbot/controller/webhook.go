@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/exp/slog"
+	"golang.org/x/xerrors"
+
+	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/view"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/cosme"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+type WebhookReplayer interface {
+	Replay(ctx context.Context, deliveryId string) (bool, error)
+}
+
+type WebhookController struct {
+	slackFactory infra_slack.SlackClientFactory
+	replayer     WebhookReplayer
+	audit        *audit.Logger
+	log          *slog.Logger
+}
+
+func NewWebhookController(
+	logger *slog.Logger,
+	slackFactory infra_slack.SlackClientFactory,
+	auditor *audit.Logger,
+	replayer WebhookReplayer,
+) *WebhookController {
+	return &WebhookController{slackFactory, replayer, auditor, logger}
+}
+
+func (c *WebhookController) Replay(ctx context.Context, ev *slackevents.AppMentionEvent, client *socketmode.Client) error {
+	channelId := ev.Channel
+
+	// new client from factory
+	sc, err := c.slackFactory.New(client.Client)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize Slack client: %w", err)
+	}
+	// parse arguments
+	s := strings.Fields(ev.Text)
+	if len(s) < 4 {
+		return api.InvalidInput(api.MsgWebhookDeliveryRequired, "webhook replay <delivery ID>")
+	}
+	deliveryId := s[3]
+
+	enqueued, err := c.replayer.Replay(ctx, deliveryId)
+	entry := auditEntry(ev.User, "webhook.replay", deliveryId, err)
+	entry.Detail = fmt.Sprintf("enqueued=%t", enqueued)
+	c.audit.Record(ctx, entry)
+	if errors.Is(err, cosme.ErrDeliveryNotFound) {
+		return api.NotFound(api.MsgWebhookDeliveryNotFound, err, deliveryId)
+	} else if err != nil {
+		return xerrors.Errorf("replayer.Replay failed: %w", err)
+	}
+
+	msg, err := view.WebhookReplayed(i18n.FromContext(ctx), deliveryId, enqueued)
+	if err != nil {
+		return xerrors.Errorf("failed to render message: %w", err)
+	}
+	if err := sc.PostMessage(ctx, channelId, msg); err != nil {
+		return xerrors.Errorf("failed to post message: %w", err)
+	}
+	return nil
+}
@@ -5,35 +5,41 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/socketmode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-
-	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+	"golang.org/x/xerrors"
 
 	"github.com/cloudnativedaysjp/seaman/cmd/seaman/config"
+	"github.com/cloudnativedaysjp/seaman/internal/admin"
 	cndoperationserver "github.com/cloudnativedaysjp/seaman/internal/infra/emtec-ecu"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/gitcommand"
 	"github.com/cloudnativedaysjp/seaman/internal/infra/githubapi"
 	infra_slack "github.com/cloudnativedaysjp/seaman/internal/infra/slack"
+	"github.com/cloudnativedaysjp/seaman/internal/metrics"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/controller"
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 	"github.com/cloudnativedaysjp/seaman/pkg/lacks"
 	seamanlog "github.com/cloudnativedaysjp/seaman/pkg/log"
 )
 
-func Run(ctx context.Context, conf *config.Config) error {
+func Run(ctx context.Context, conf *config.Config, webhookReplayer controller.WebhookReplayer,
+	auditor *audit.Logger, adminServer *admin.Server,
+) error {
 	logger := seamanlog.FromContext(ctx)
 
 	// setup Slack Bot
+	httpClient := infra_slack.NewHTTPClient()
 	var client *socketmode.Client
 	if conf.Debug {
 		client = socketmode.New(
 			slack.New(
 				conf.Slack.BotToken,
 				slack.OptionAppLevelToken(conf.Slack.AppToken),
+				slack.OptionHTTPClient(httpClient),
 				slack.OptionDebug(true),
 				slack.OptionLog(log.New(os.Stdout, "api: ", log.Lshortfile|log.LstdFlags)),
 			),
@@ -45,39 +51,77 @@ func Run(ctx context.Context, conf *config.Config) error {
 			slack.New(
 				conf.Slack.BotToken,
 				slack.OptionAppLevelToken(conf.Slack.AppToken),
+				slack.OptionHTTPClient(httpClient),
 			),
 		)
 	}
 
-	r := lacks.NewRouter(logger, client)
+	channelLangs := make(map[string]i18n.Lang)
+	for channelId, lang := range conf.Slack.Language.Channels {
+		channelLangs[channelId] = i18n.Lang(lang)
+	}
+	languageResolver := controller.NewLanguageResolver(controller.LanguagePolicy{
+		Default:    i18n.Lang(conf.Slack.Language.Default),
+		Channels:   channelLangs,
+		UserLocale: conf.Slack.Language.UserLocale,
+	})
+	r := lacks.NewRouter(logger, client).
+		WithObserver(metrics.ObserveHandler).
+		WithTimeout(time.Duration(conf.Slack.HandlerTimeoutSeconds) * time.Second).
+		WithContextFunc(languageResolver.IntoContext)
 
 	// setup some instances
-	slackFactory := infra_slack.NewSlackClientFactory()
+	if conf.Slack.Callback.SigningKey == "" {
+		logger.Warn("slack.callback.signingKey is empty, so buttons posted before restart cannot be used")
+	}
+	signer := api.NewSigner([]byte(conf.Slack.Callback.SigningKey),
+		time.Duration(conf.Slack.Callback.TTLSeconds)*time.Second)
+	replyPolicy := controller.ReplyPolicy(conf.Slack.Reply)
+	slackFactory := controller.NewReplySlackClientFactory(infra_slack.NewSlackClientFactory(), replyPolicy)
 	githubApiClient := githubapi.NewGitHubApiClientImpl(conf.GitHub.AccessToken)
 	gitCommandClient := gitcommand.NewGitCommandClientImpl(conf.GitHub.Username, conf.GitHub.AccessToken)
-	var cndClient *cndoperationserver.CndWrapper
-	if conf.Emtec.EndpointUrl != "" {
-		func() {
-			conn, err := grpc.NewClient(conf.Emtec.EndpointUrl,
-				grpc.WithTransportCredentials(insecure.NewCredentials()), // TODO (cloudnativedaysjp/emtec-ecu#7)
-			)
-			if err != nil {
-				logger.Warn(fmt.Sprintf("cannot connect to EMTEC-ECU, skipped: %v", err))
-				return
+	var emtecEvents []controller.EmtecEvent
+	for _, event := range conf.Emtec.AllEvents() {
+		conn, err := cndoperationserver.Dial(event.EndpointUrl, cndoperationserver.DialOptions{
+			TLS:           cndoperationserver.TLSOptions(event.TLS),
+			MaxAttempts:   conf.Emtec.MaxAttempts,
+			KeepaliveTime: time.Duration(conf.Emtec.KeepaliveSeconds) * time.Second,
+		})
+		if err != nil {
+			// commands are still registered and reply "EMTEC unavailable"
+			logger.Warn(fmt.Sprintf("cannot connect to EMTEC-ECU of %s: %v", event.Name, err))
+		}
+		wrapper := cndoperationserver.NewCndWrapperFromConn(conn, err).
+			WithTimeout(time.Duration(conf.Emtec.TimeoutSeconds) * time.Second)
+		emtecEvents = append(emtecEvents, controller.EmtecEvent{
+			Name:     event.Name,
+			Client:   wrapper,
+			Channels: event.Channels,
+		})
+		adminServer.AddReadinessCheck("emtec/"+event.Name, func(context.Context) error {
+			if ready, state := wrapper.Ready(); !ready {
+				return xerrors.Errorf("connection is %s", state)
 			}
-			cndClient = cndoperationserver.NewCndWrapper(
-				pb.NewSceneServiceClient(conn), pb.NewTrackServiceClient(conn),
-			)
-		}()
+			return nil
+		})
 	}
 
+	// readiness
+	adminServer.AddReadinessCheck("slack", func(context.Context) error {
+		if !r.Connected() {
+			return xerrors.New("socket mode is not connected")
+		}
+		return nil
+	})
+	adminServer.AddReadinessCheck("github", githubApiClient.HealthCheck)
+
 	{ // release
 		var targets []controller.Target
 		for _, target := range conf.Release.Targets {
 			targets = append(targets, controller.Target(target))
 		}
 		c := controller.NewReleaseController(logger,
-			slackFactory, gitCommandClient, githubApiClient, targets)
+			slackFactory, auditor, signer, gitCommandClient, githubApiClient, targets)
 		// socketmodeHandler.HandleEvents(
 		// 	slackevents.AppMention, middleware.MiddlewareSet(c.SelectRepository,
 		// 		middleware.RegisterCommand("release").
@@ -96,9 +140,22 @@ func Run(ctx context.Context, conf *config.Config) error {
 			api.ActIdRelease_SelectedLevelPatch, c.SelectConfirmation)
 		r.HandleInteractionBlockAction(
 			api.ActIdRelease_OK, c.CreatePullRequestForRelease)
+		r.HandleInteractionBlockAction(
+			api.ActIdRelease_OpenModal, c.OpenReleaseModal)
+		r.HandleViewSubmission(
+			api.CallbackIdRelease_Modal, c.SubmitReleaseModal)
 	}
-	if cndClient != nil { // emtec
-		c := controller.NewEmtecController(logger, slackFactory, cndClient)
+	var emtecController *controller.EmtecController
+	if len(emtecEvents) != 0 { // emtec
+		location, err := time.LoadLocation(conf.Emtec.TimeZone)
+		if err != nil {
+			return xerrors.Errorf("invalid emtec.timeZone: %w", err)
+		}
+		c := controller.NewEmtecController(logger, slackFactory, auditor, signer, emtecEvents).
+			WithSceneSwitch(conf.Emtec.SceneSwitch.ConfirmTracks,
+				time.Duration(conf.Emtec.SceneSwitch.UndoWindowSeconds)*time.Second).
+			WithTimeZone(location)
+		emtecController = c
 		r.HandleMentionedMessage(
 			"emtec list-track", c.ListTrack).
 			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
@@ -108,12 +165,75 @@ func Run(ctx context.Context, conf *config.Config) error {
 		r.HandleMentionedMessage(
 			"emtec disable-track", c.DisableAutomation).
 			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec list-scene", c.ListScene).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec next-scene", c.NextScene).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec dashboard", c.Dashboard).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec schedule enable-track", c.ScheduleEnableAutomation).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec schedule disable-track", c.ScheduleDisableAutomation).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec schedule list", c.ListSchedule).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
+		r.HandleMentionedMessage(
+			"emtec schedule cancel", c.CancelSchedule).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/emtec.md")
 		r.HandleInteractionBlockAction(
 			api.ActIdEmtec_SceneNext, c.UpdateSceneToNext)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_SceneUndo, c.UndoSceneSwitch)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_DashboardToggleAutomation, c.DashboardToggleAutomation)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_DashboardSceneNext, c.DashboardSceneNext)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_DashboardRefresh, c.DashboardRefresh)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_SelectedTrackEnable, c.SelectedTrackToEnable)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_SelectedTrackDisable, c.SelectedTrackToDisable)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_SwitchAllEnable, c.SwitchAllToEnable)
+		r.HandleInteractionBlockAction(
+			api.ActIdEmtec_SwitchAllDisable, c.SwitchAllToDisable)
+		var timetable []controller.EmtecTimetableEntry
+		for _, entry := range conf.Emtec.Timetable {
+			timetable = append(timetable, controller.EmtecTimetableEntry{
+				Event: entry.Event, Track: entry.Track, Enabled: entry.Action == "enable-track",
+				At: entry.At, ChannelId: entry.Channel, Lang: languageResolver.ForChannel(entry.Channel),
+			})
+		}
+		go c.RunScheduler(ctx, client.Client, timetable)
+		if interval := conf.Emtec.Poller.IntervalSeconds; interval > 0 {
+			go c.RunPoller(ctx, client.Client,
+				time.Duration(interval)*time.Second, conf.Emtec.Poller.NotificationChannel,
+				languageResolver.ForChannel(conf.Emtec.Poller.NotificationChannel))
+		}
+	}
+	if webhookReplayer != nil { // webhook
+		c := controller.NewWebhookController(logger, slackFactory, auditor, webhookReplayer)
+		r.HandleMentionedMessage(
+			"webhook replay", c.Replay).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/slack/webhook.md")
+	}
+	{ // audit
+		c := controller.NewAuditController(logger, slackFactory, auditor)
+		r.HandleMentionedMessage(
+			"audit", c.ShowRecent).
+			WithURL("https://github.com/cloudnativedaysjp/seaman/blob/main/docs/slack/audit.md")
 	}
 	{ // common
 		c := controller.NewCommonController(logger,
-			slackFactory)
+			slackFactory).WithReplyPolicy(replyPolicy)
+		r.WithErrorHandler(c.HandleError)
 		r.HandleHelp(c.ShowCommands)
 		r.HandleMentionedMessage("version", c.ShowVersion)
 		r.HandleInteractionBlockAction(
@@ -122,8 +242,21 @@ func Run(ctx context.Context, conf *config.Config) error {
 			api.ActIdCommon_Cancel, c.InteractionCancel)
 	}
 
-	if err := r.RunEventLoop(); err != nil {
+	if err := r.RunEventLoop(ctx); err != nil {
 		return err
 	}
+
+	logger.Info("shutting down Slack bot")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx),
+		time.Duration(conf.ShutdownGracePeriodSeconds)*time.Second)
+	defer cancel()
+	if err := r.Shutdown(shutdownCtx); err != nil {
+		logger.Warn(fmt.Sprintf("in-flight handlers are interrupted: %v", err))
+	}
+	if emtecController != nil {
+		if err := emtecController.DrainPendingSceneSwitches(shutdownCtx); err != nil {
+			logger.Warn(err.Error())
+		}
+	}
 	return nil
 }
@@ -0,0 +1,37 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+var auditOutcomeEmoji = map[string]string{
+	audit.OutcomeSuccess: ":white_check_mark:",
+	audit.OutcomeFailure: ":x:",
+	audit.OutcomeIgnored: ":heavy_minus_sign:",
+}
+
+func AuditRecent(lang i18n.Lang, entries []audit.Entry) (slack.Msg, error) {
+	lines := []string{catalog.Sprintf(lang, msgAuditRecent, len(entries))}
+	for _, e := range entries {
+		actor := fmt.Sprintf("%s:%s", e.Source, e.Actor)
+		if e.Source == audit.SourceSlack {
+			actor = fmt.Sprintf("<@%s>", e.Actor)
+		}
+		line := fmt.Sprintf("%s `%s` %s `%s` %s",
+			auditOutcomeEmoji[e.Outcome], e.Time.Format("2006-01-02 15:04:05 MST"), actor, e.Action, e.Target)
+		if e.Detail != "" {
+			line += fmt.Sprintf(" (%s)", e.Detail)
+		}
+		lines = append(lines, line)
+	}
+	if len(entries) == 0 {
+		lines = []string{catalog.Sprintf(lang, msgAuditNothing)}
+	}
+	return blocksMessage(section(mrkdwn(strings.Join(lines, "\n"))))
+}
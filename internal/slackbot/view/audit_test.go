@@ -0,0 +1,33 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/audit"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+func Test_auditRecent(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2023, 12, 11, 12, 0, 0, 0, time.UTC)
+	entries := []audit.Entry{
+		{Time: at, Source: audit.SourceSlack, Actor: "U0123456789",
+			Action: "emtec.enable-track", Target: "A", Outcome: audit.OutcomeSuccess},
+		{Time: at, Source: audit.SourceGitHub, Actor: "ShotaKitazawa",
+			Action: "github.separate", Target: "cloudnativedaysjp/dreamkast-infra#1",
+			Outcome: audit.OutcomeFailure, Detail: "envs=dev"},
+	}
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(AuditRecent(i18n.English, entries))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assertGolden(t)(AuditRecent(i18n.English, nil))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(AuditRecent(i18n.Japanese, entries))
+	})
+	t.Run("empty in Japanese", func(t *testing.T) {
+		assertGolden(t)(AuditRecent(i18n.Japanese, nil))
+	})
+}
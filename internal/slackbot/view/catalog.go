@@ -0,0 +1,511 @@
+package view
+
+import (
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+// keys of catalog
+const (
+	msgSelectItem = "selectItem"
+	msgCancel     = "cancel"
+	msgOk         = "ok"
+
+	msgCommonCommands         = "common.commands"
+	msgCommonCanceled         = "common.canceled"
+	msgCommonSomethingIsWrong = "common.somethingIsWrong"
+
+	msgReleaseSelectRepository = "release.selectRepository"
+	msgReleaseModalTitle       = "release.modalTitle"
+	msgReleaseOpenModal        = "release.openModal"
+	msgReleaseCreatePr         = "release.createPr"
+	msgReleaseRepository       = "release.repository"
+	msgReleaseLevel            = "release.level"
+	msgReleaseNotes            = "release.notes"
+	msgReleaseSelectLevel      = "release.selectLevel"
+	msgReleaseConfirm          = "release.confirm"
+	msgReleaseProcessing       = "release.processing"
+	msgReleaseTarget           = "release.target"
+	msgReleaseUpdateLevel      = "release.updateLevel"
+
+	msgEmtecEnabled             = "emtec.enabled"
+	msgEmtecDisabled            = "emtec.disabled"
+	msgEmtecSelectTrackEnable   = "emtec.selectTrackEnable"
+	msgEmtecSelectTrackDisable  = "emtec.selectTrackDisable"
+	msgEmtecSwitchAllEnable     = "emtec.switchAllEnable"
+	msgEmtecSwitchAllDisable    = "emtec.switchAllDisable"
+	msgEmtecSwitchedAllEnabled  = "emtec.switchedAllEnabled"
+	msgEmtecSwitchedAllDisabled = "emtec.switchedAllDisabled"
+
+	msgEmtecTrack                    = "emtec.track"
+	msgEmtecCurrentScene             = "emtec.currentScene"
+	msgEmtecNoScenes                 = "emtec.noScenes"
+	msgEmtecSceneFromTo              = "emtec.sceneFromTo"
+	msgEmtecNextScene                = "emtec.nextScene"
+	msgEmtecMoveToNextScene          = "emtec.moveToNextScene"
+	msgEmtecSwitch                   = "emtec.switch"
+	msgEmtecSwitched                 = "emtec.switched"
+	msgEmtecNextSceneConfirmTitle    = "emtec.nextSceneConfirmTitle"
+	msgEmtecNextSceneConfirmText     = "emtec.nextSceneConfirmText"
+	msgEmtecUndo                     = "emtec.undo"
+	msgEmtecSwitchPushed             = "emtec.switchPushed"
+	msgEmtecSwitchIgnored            = "emtec.switchIgnored"
+	msgEmtecSwitchUndone             = "emtec.switchUndone"
+	msgEmtecUndoTooLate              = "emtec.undoTooLate"
+	msgEmtecSwitchCanceledOnShutdown = "emtec.switchCanceledOnShutdown"
+
+	msgEmtecDashboard          = "emtec.dashboard"
+	msgEmtecDashboardTrack     = "emtec.dashboardTrack"
+	msgEmtecDashboardScene     = "emtec.dashboardScene"
+	msgEmtecDashboardUpdated   = "emtec.dashboardUpdated"
+	msgEmtecDashboardUpdatedBy = "emtec.dashboardUpdatedBy"
+	msgEmtecEnable             = "emtec.enable"
+	msgEmtecDisable            = "emtec.disable"
+	msgEmtecRefresh            = "emtec.refresh"
+	msgEmtecEnabledBy          = "emtec.enabledBy"
+	msgEmtecDisabledBy         = "emtec.disabledBy"
+	msgEmtecSceneSwitchedBy    = "emtec.sceneSwitchedBy"
+
+	msgEmtecChanged            = "emtec.changed"
+	msgEmtecChangeTrackAdded   = "emtec.changeTrackAdded"
+	msgEmtecChangeTrackRemoved = "emtec.changeTrackRemoved"
+	msgEmtecChangeEnabled      = "emtec.changeEnabled"
+	msgEmtecChangeDisabled     = "emtec.changeDisabled"
+	msgEmtecChangeScene        = "emtec.changeScene"
+
+	msgEmtecScheduleItemEnable  = "emtec.scheduleItemEnable"
+	msgEmtecScheduleItemDisable = "emtec.scheduleItemDisable"
+	msgEmtecScheduled           = "emtec.scheduled"
+	msgEmtecSchedulePending     = "emtec.schedulePending"
+	msgEmtecScheduleNothing     = "emtec.scheduleNothing"
+	msgEmtecScheduleInMemory    = "emtec.scheduleInMemory"
+	msgEmtecScheduleCanceled    = "emtec.scheduleCanceled"
+	msgEmtecScheduleExecuted    = "emtec.scheduleExecuted"
+	msgEmtecScheduleFailed      = "emtec.scheduleFailed"
+	msgEmtecTimetableRejected   = "emtec.timetableRejected"
+
+	msgAuditRecent  = "audit.recent"
+	msgAuditNothing = "audit.nothing"
+
+	msgWebhookReplayed          = "webhook.replayed"
+	msgWebhookReplayedNoCommand = "webhook.replayedNoCommand"
+)
+
+// catalog is the messages shown in Slack. The formats of a key take the same arguments
+// in every language (use explicit argument indexes if the order differs).
+var catalog = i18n.Catalog{
+	msgSelectItem: {
+		i18n.Japanese: "選択してください",
+		i18n.English:  "Select an item",
+	},
+	msgCancel: {
+		i18n.Japanese: "キャンセル",
+		i18n.English:  "Cancel",
+	},
+	msgOk: {
+		i18n.Japanese: "OK",
+		i18n.English:  "OK",
+	},
+
+	msgCommonCommands: {
+		i18n.Japanese: "以下のコマンドが存在します。",
+		i18n.English:  "The following commands are available.",
+	},
+	msgCommonCanceled: {
+		i18n.Japanese: "キャンセルされました",
+		i18n.English:  "Canceled",
+	},
+	msgCommonSomethingIsWrong: {
+		i18n.Japanese: "アプリケーションのログを確認してください",
+		i18n.English:  "Please confirm to application log",
+	},
+
+	msgReleaseSelectRepository: {
+		i18n.Japanese: "リリース対象のリポジトリを選択",
+		i18n.English:  "Select the repository to release",
+	},
+	msgReleaseModalTitle: {
+		// at most 24 characters (the limit of the title of modals)
+		i18n.Japanese: "リリース",
+		i18n.English:  "Release",
+	},
+	msgReleaseOpenModal: {
+		i18n.Japanese: "フォームで入力",
+		i18n.English:  "Fill in the form",
+	},
+	msgReleaseCreatePr: {
+		i18n.Japanese: "PR を作成",
+		i18n.English:  "Create PR",
+	},
+	msgReleaseRepository: {
+		i18n.Japanese: "リリース対象のリポジトリ",
+		i18n.English:  "Repository to release",
+	},
+	msgReleaseLevel: {
+		i18n.Japanese: "更新レベル",
+		i18n.English:  "Update level",
+	},
+	msgReleaseNotes: {
+		i18n.Japanese: "リリースノート",
+		i18n.English:  "Release notes",
+	},
+	msgReleaseSelectLevel: {
+		i18n.Japanese: "更新レベルを選択",
+		i18n.English:  "Select the update level",
+	},
+	// arguments: organization, repository and level
+	msgReleaseConfirm: {
+		i18n.Japanese: "リリースしますか？ > 対象: *%s/%s*, 更新レベル: *%s*",
+		i18n.English:  "OK? > Target: *%s/%s*, Update Level: *%s*",
+	},
+	msgReleaseProcessing: {
+		i18n.Japanese: "処理中...",
+		i18n.English:  "processing...",
+	},
+	msgReleaseTarget: {
+		i18n.Japanese: "対象: *%s/%s*",
+		i18n.English:  "Target: *%s/%s*",
+	},
+	msgReleaseUpdateLevel: {
+		i18n.Japanese: "更新レベル: *%s*",
+		i18n.English:  "Update Level: *%s*",
+	},
+
+	msgEmtecEnabled: {
+		i18n.Japanese: "Track %s の自動切り替えを有効化しました",
+		i18n.English:  "Enabled automated switching of Track %s",
+	},
+	msgEmtecDisabled: {
+		i18n.Japanese: "Track %s の自動切り替えを無効化しました",
+		i18n.English:  "Disabled automated switching of Track %s",
+	},
+	msgEmtecSelectTrackEnable: {
+		i18n.Japanese: "自動切り替えを有効化するトラックを選択",
+		i18n.English:  "Select the track to enable automated switching",
+	},
+	msgEmtecSelectTrackDisable: {
+		i18n.Japanese: "自動切り替えを無効化するトラックを選択",
+		i18n.English:  "Select the track to disable automated switching",
+	},
+	msgEmtecSwitchAllEnable: {
+		i18n.Japanese: "全トラックの自動切り替えを有効化しますか？",
+		i18n.English:  "Enable automated switching of all tracks?",
+	},
+	msgEmtecSwitchAllDisable: {
+		i18n.Japanese: "全トラックの自動切り替えを無効化しますか？",
+		i18n.English:  "Disable automated switching of all tracks?",
+	},
+	msgEmtecSwitchedAllEnabled: {
+		i18n.Japanese: "%d トラックの自動切り替えを有効化しました",
+		i18n.English:  "Enabled automated switching of %d tracks",
+	},
+	msgEmtecSwitchedAllDisabled: {
+		i18n.Japanese: "%d トラックの自動切り替えを無効化しました",
+		i18n.English:  "Disabled automated switching of %d tracks",
+	},
+
+	msgEmtecTrack: {
+		i18n.Japanese: "トラック: *%s*",
+		i18n.English:  "Track: *%s*",
+	},
+	// arguments: index and name of the scene
+	msgEmtecCurrentScene: {
+		i18n.Japanese: ":arrow_forward: *%d: %s* (放送中)",
+		i18n.English:  ":arrow_forward: *%d: %s* (current)",
+	},
+	msgEmtecNoScenes: {
+		i18n.Japanese: "シーンはありません",
+		i18n.English:  "no scenes",
+	},
+	msgEmtecSceneFromTo: {
+		i18n.Japanese: "シーン: *%s* → *%s*",
+		i18n.English:  "Scene: *%s* → *%s*",
+	},
+	msgEmtecNextScene: {
+		i18n.Japanese: "次のシーン",
+		i18n.English:  "Next Scene",
+	},
+	msgEmtecMoveToNextScene: {
+		i18n.Japanese: "次のシーンに切り替えますか？",
+		i18n.English:  "Move to the next scene?",
+	},
+	msgEmtecSwitch: {
+		i18n.Japanese: "切り替え",
+		i18n.English:  "Switching",
+	},
+	msgEmtecSwitched: {
+		i18n.Japanese: ":white_check_mark: 切り替え済み",
+		i18n.English:  ":white_check_mark: Switched",
+	},
+	msgEmtecNextSceneConfirmTitle: {
+		i18n.Japanese: "次のシーンに切り替え",
+		i18n.English:  "Move to Next Scene",
+	},
+	msgEmtecNextSceneConfirmText: {
+		i18n.Japanese: "Track %s: よろしいですか？",
+		i18n.English:  "Track %s: are you sure?",
+	},
+	msgEmtecUndo: {
+		i18n.Japanese: ":leftwards_arrow_with_hook: 元に戻す (%d 秒後に切り替え)",
+		i18n.English:  ":leftwards_arrow_with_hook: Undo (switching in %ds)",
+	},
+	msgEmtecSwitchPushed: {
+		i18n.Japanese: "<@%s> が切り替えを実行しました",
+		i18n.English:  "Switching was pushed by <@%s>",
+	},
+	// arguments: track and user
+	msgEmtecSwitchIgnored: {
+		i18n.Japanese: "Track %s のシーンは既に切り替わっているため、 <@%s> のクリックは無視されました",
+		i18n.English:  "Scene of Track %s has already been switched, so the click by <@%s> was ignored",
+	},
+	msgEmtecSwitchUndone: {
+		i18n.Japanese: "<@%s> が切り替えを取り消しました",
+		i18n.English:  "Switching was undone by <@%s>",
+	},
+	msgEmtecUndoTooLate: {
+		i18n.Japanese: "<@%s> シーンは既に切り替わっているため、取り消せません",
+		i18n.English:  "<@%s> Too late to undo: the scene has already been switched",
+	},
+	msgEmtecSwitchCanceledOnShutdown: {
+		i18n.Japanese: "<@%s> seaman が停止するため、切り替えはキャンセルされました。再起動後にもう一度ボタンを押してください",
+		i18n.English:  "<@%s> Switching was canceled because seaman is shutting down. Please click the button again after restart",
+	},
+
+	msgEmtecDashboard: {
+		i18n.Japanese: "EMTEC ダッシュボード",
+		i18n.English:  "EMTEC Dashboard",
+	},
+	// arguments: name, ID, OBS host and automation of the track
+	msgEmtecDashboardTrack: {
+		i18n.Japanese: "*Track %s* (%d)\nOBS: %s\n自動切り替え: %s",
+		i18n.English:  "*Track %s* (%d)\nOBS: %s\nAutomation: %s",
+	},
+	msgEmtecDashboardScene: {
+		i18n.Japanese: "現在のシーン: *%s*",
+		i18n.English:  "Current Scene: *%s*",
+	},
+	msgEmtecDashboardUpdated: {
+		i18n.Japanese: "最終更新: %s",
+		i18n.English:  "Last updated: %s",
+	},
+	// arguments: time and user
+	msgEmtecDashboardUpdatedBy: {
+		i18n.Japanese: "最終更新: %s (<@%s>)",
+		i18n.English:  "Last updated: %s by <@%s>",
+	},
+	msgEmtecEnable: {
+		i18n.Japanese: "有効化",
+		i18n.English:  "Enable",
+	},
+	msgEmtecDisable: {
+		i18n.Japanese: "無効化",
+		i18n.English:  "Disable",
+	},
+	msgEmtecRefresh: {
+		i18n.Japanese: "更新",
+		i18n.English:  "Refresh",
+	},
+	// arguments: track and user
+	msgEmtecEnabledBy: {
+		i18n.Japanese: "<@%[2]s> が Track %[1]s の自動切り替えを有効化しました",
+		i18n.English:  "Automation of Track %s was enabled by <@%s>",
+	},
+	msgEmtecDisabledBy: {
+		i18n.Japanese: "<@%[2]s> が Track %[1]s の自動切り替えを無効化しました",
+		i18n.English:  "Automation of Track %s was disabled by <@%s>",
+	},
+	msgEmtecSceneSwitchedBy: {
+		i18n.Japanese: "<@%[2]s> が Track %[1]s のシーンを切り替えました",
+		i18n.English:  "Scene of Track %s was switched by <@%s>",
+	},
+
+	msgEmtecChanged: {
+		i18n.Japanese: ":satellite_antenna: EMTEC の状態が変化しました",
+		i18n.English:  ":satellite_antenna: EMTEC state was changed",
+	},
+	msgEmtecChangeTrackAdded: {
+		i18n.Japanese: "Track %s が追加されました",
+		i18n.English:  "Track %s was added",
+	},
+	msgEmtecChangeTrackRemoved: {
+		i18n.Japanese: "Track %s が削除されました",
+		i18n.English:  "Track %s was removed",
+	},
+	msgEmtecChangeEnabled: {
+		i18n.Japanese: "Track %s の自動切り替えが有効化されました",
+		i18n.English:  "Automation of Track %s was enabled",
+	},
+	msgEmtecChangeDisabled: {
+		i18n.Japanese: "Track %s の自動切り替えが無効化されました",
+		i18n.English:  "Automation of Track %s was disabled",
+	},
+	// arguments: track, scene before and scene after
+	msgEmtecChangeScene: {
+		i18n.Japanese: "Track %s のシーンが切り替わりました: %s → %s",
+		i18n.English:  "Scene of Track %s was changed: %s → %s",
+	},
+
+	// arguments: ID, time, track and creator
+	msgEmtecScheduleItemEnable: {
+		i18n.Japanese: "#%d: %s に Track %s の自動切り替えを有効化 (by %s)",
+		i18n.English:  "#%[1]d: Enable automated switching of Track %[3]s at %[2]s (by %[4]s)",
+	},
+	msgEmtecScheduleItemDisable: {
+		i18n.Japanese: "#%d: %s に Track %s の自動切り替えを無効化 (by %s)",
+		i18n.English:  "#%[1]d: Disable automated switching of Track %[3]s at %[2]s (by %[4]s)",
+	},
+	msgEmtecScheduled: {
+		i18n.Japanese: ":alarm_clock: スケジュールを登録しました",
+		i18n.English:  ":alarm_clock: Scheduled",
+	},
+	msgEmtecSchedulePending: {
+		i18n.Japanese: "*実行待ちのスケジュール*",
+		i18n.English:  "*Pending schedules*",
+	},
+	msgEmtecScheduleNothing: {
+		i18n.Japanese: "スケジュールはありません",
+		i18n.English:  "no schedules",
+	},
+	msgEmtecScheduleInMemory: {
+		i18n.Japanese: "スケジュールはメモリ上に保持されるため、 seaman を再起動すると失われます (`emtec.timetable` のものは起動時に再登録されます)",
+		i18n.English:  "Schedules are kept in memory and lost when seaman restarts (those of `emtec.timetable` are registered again on startup)",
+	},
+	msgEmtecScheduleCanceled: {
+		i18n.Japanese: "スケジュールが <@%s> によってキャンセルされました",
+		i18n.English:  "The schedule was canceled by <@%s>",
+	},
+	msgEmtecScheduleExecuted: {
+		i18n.Japanese: ":white_check_mark: スケジュールを実行しました",
+		i18n.English:  ":white_check_mark: Executed the schedule",
+	},
+	msgEmtecScheduleFailed: {
+		i18n.Japanese: ":x: スケジュールの実行に失敗しました: %s",
+		i18n.English:  ":x: Failed to execute the schedule: %s",
+	},
+	msgEmtecTimetableRejected: {
+		i18n.Japanese: ":x: `emtec.timetable` に実行できないエントリがあるため、どのエントリも登録されませんでした。設定を修正して seaman を再起動してください (# は `emtec.timetable` での順番です)",
+		i18n.English:  ":x: No entry of `emtec.timetable` was registered because of the invalid entries below. Please fix the config and restart seaman (# is the position in `emtec.timetable`)",
+	},
+
+	msgAuditRecent: {
+		i18n.Japanese: "*最近の監査ログ %d 件*",
+		i18n.English:  "*Recent %d audit entries*",
+	},
+	msgAuditNothing: {
+		i18n.Japanese: "監査ログはありません",
+		i18n.English:  "no audit entries",
+	},
+
+	msgWebhookReplayed: {
+		i18n.Japanese: "Delivery `%s` を再実行しました",
+		i18n.English:  "Delivery `%s` has been replayed",
+	},
+	msgWebhookReplayedNoCommand: {
+		i18n.Japanese: "Delivery `%s` を再実行しましたが、処理するコマンドはありませんでした",
+		i18n.English:  "Delivery `%s` has been replayed, but it contains no command to be processed",
+	},
+
+	// messages of api.Error
+	api.MsgInvalidCallbackValue: {
+		i18n.Japanese: "ボタンまたはメニューの値が不正です: %v",
+		i18n.English:  "invalid callback value: %v",
+	},
+	api.MsgCallbackOutdated: {
+		i18n.Japanese: "このボタンは古い形式です。コマンドを再度実行してください",
+		i18n.English:  "this button is outdated, please run the command again",
+	},
+	api.MsgCallbackTampered: {
+		i18n.Japanese: "ボタンまたはメニューの値が不正です",
+		i18n.English:  "invalid callback value",
+	},
+	api.MsgCallbackExpired: {
+		i18n.Japanese: "このボタンは有効期限が切れています。コマンドを再度実行してください",
+		i18n.English:  "this button has expired, please run the command again",
+	},
+	api.MsgGitHubUnavailable: {
+		i18n.Japanese: "GitHub が一時的に利用できません。しばらくしてから再度実行してください",
+		i18n.English:  "GitHub is temporarily unavailable. Please retry later.",
+	},
+	api.MsgReleaseInvalidLevel: {
+		i18n.Japanese: "不正な更新レベルです: %s",
+		i18n.English:  "invalid release level: %s",
+	},
+	// arguments: organization and repository
+	api.MsgReleaseNotTarget: {
+		i18n.Japanese: "%s/%s はリリース対象ではありません",
+		i18n.English:  "%s/%s is not a release target",
+	},
+	// arguments: the list of events
+	api.MsgEmtecEventRequired: {
+		i18n.Japanese: "--event を指定してください (イベント: %s)",
+		i18n.English:  "--event is required (events: %s)",
+	},
+	// arguments: event and the list of events
+	api.MsgEmtecEventUnknown: {
+		i18n.Japanese: "不明なイベントです: %s (イベント: %s)",
+		i18n.English:  "unknown event: %s (events: %s)",
+	},
+	api.MsgEmtecUnavailable: {
+		i18n.Japanese: "EMTEC-ECU に接続できません。しばらくしてから再度実行してください",
+		i18n.English:  "Cannot connect to EMTEC-ECU. Please retry later.",
+	},
+	api.MsgEmtecUnavailableState: {
+		i18n.Japanese: "EMTEC-ECU に接続できません (状態: `%s`)。しばらくしてから再度実行してください",
+		i18n.English:  "Cannot connect to EMTEC-ECU (state: `%s`). Please retry later.",
+	},
+	// arguments: usage
+	api.MsgEmtecTrackRequired: {
+		i18n.Japanese: "トラックを指定してください。使い方: `%s`",
+		i18n.English:  "track is not specified. Usage: `%s`",
+	},
+	api.MsgEmtecTrackNotFound: {
+		i18n.Japanese: "トラック %s が見つかりません",
+		i18n.English:  "track %s is not found",
+	},
+	// arguments: track and candidates
+	api.MsgEmtecTrackAmbiguous: {
+		i18n.Japanese: "トラック %s が複数のトラックに一致します (候補: %s)",
+		i18n.English:  "track %s is ambiguous (candidates: %s)",
+	},
+	// arguments: usage
+	api.MsgEmtecScheduleUsage: {
+		i18n.Japanese: "引数が不正です。使い方: `%s`",
+		i18n.English:  "invalid arguments. Usage: `%s`",
+	},
+	api.MsgEmtecScheduleTimeInvalid: {
+		i18n.Japanese: "不正な時刻です: %s (HH:MM または RFC3339 で指定してください)",
+		i18n.English:  "invalid time: %s (must be HH:MM or RFC3339)",
+	},
+	api.MsgEmtecScheduleTimePast: {
+		i18n.Japanese: "%s は過去の時刻です",
+		i18n.English:  "%s is in the past",
+	},
+	// arguments: usage
+	api.MsgEmtecScheduleIdRequired: {
+		i18n.Japanese: "スケジュール ID を 1 つ指定してください。使い方: `%s`",
+		i18n.English:  "specify one schedule ID. Usage: `%s`",
+	},
+	// arguments: schedule ID and usage
+	api.MsgEmtecScheduleIdInvalid: {
+		i18n.Japanese: "不正なスケジュール ID です: %s。使い方: `%s`",
+		i18n.English:  "invalid schedule ID: %s. Usage: `%s`",
+	},
+	api.MsgEmtecScheduleNotFound: {
+		i18n.Japanese: "スケジュール #%d が見つかりません",
+		i18n.English:  "schedule #%d is not found",
+	},
+	// arguments: the maximum and usage
+	api.MsgAuditInvalidCount: {
+		i18n.Japanese: "件数は 1 から %d までの整数で指定してください。使い方: `%s`",
+		i18n.English:  "the number of entries must be an integer between 1 and %d. Usage: `%s`",
+	},
+	// arguments: usage
+	api.MsgWebhookDeliveryRequired: {
+		i18n.Japanese: "Delivery ID を指定してください。使い方: `%s`",
+		i18n.English:  "delivery ID is not specified. Usage: `%s`",
+	},
+	api.MsgWebhookDeliveryNotFound: {
+		i18n.Japanese: "Delivery %s は最近の配信に見つかりません",
+		i18n.English:  "delivery %s is not found in recent deliveries",
+	},
+}
@@ -0,0 +1,9 @@
+package view
+
+import "testing"
+
+func Test_catalog(t *testing.T) {
+	if missing := catalog.Missing(); len(missing) != 0 {
+		t.Errorf("messages are not translated: %v", missing)
+	}
+}
@@ -6,126 +6,69 @@ import (
 	"strings"
 
 	"github.com/slack-go/slack"
-)
 
-func ShowCommands(commands map[string]string) slack.Msg {
-	result, _ := showCommands(commands)
-	return result
-}
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
 
-func showCommands(commands map[string]string) (slack.Msg, error) {
-	var cmds []struct {
-		command string
-		url     string
-	}
-	for k, v := range commands {
-		cmds = append(cmds, struct {
-			command string
-			url     string
-		}{k, v})
+func ShowCommands(lang i18n.Lang, commands map[string]string) (slack.Msg, error) {
+	var names []string
+	for command := range commands {
+		names = append(names, command)
 	}
-	sort.SliceStable(cmds, func(i, j int) bool { return cmds[i].command < cmds[j].command })
+	sort.Strings(names)
 
 	var msg []string
-	for _, c := range cmds {
-		if c.url != "" {
-			msg = append(msg, fmt.Sprintf("• <%s|%s>", c.url, c.command))
+	for _, command := range names {
+		if url := commands[command]; url != "" {
+			msg = append(msg, fmt.Sprintf("• <%s|%s>", url, command))
 		} else {
-			msg = append(msg, fmt.Sprintf("• %s", c.command))
+			msg = append(msg, fmt.Sprintf("• %s", command))
 		}
 	}
-	return castFromStringToMsg(replaceBackquote(fmt.Sprintf(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "以下のコマンドが存在します。\n<backquote><backquote><backquote>%s<backquote><backquote><backquote>"
-			}
-		}
-	]
-}
-`, strings.Join(msg, `\n`),
-	)))
+	return blocksMessage(
+		section(mrkdwn(fmt.Sprintf("%s\n```%s```",
+			catalog.Sprintf(lang, msgCommonCommands), strings.Join(msg, "\n")))),
+	)
 }
 
-func InvalidArguments(messageTs, message string) slack.Msg {
-	result, _ := invalidArguments(messageTs, message)
-	return result
+func InvalidArguments(messageTs, message string) (slack.Msg, error) {
+	return errorMessage(api.ErrorKindInvalidInput, message, messageTs)
 }
 
-func invalidArguments(messageTs, message string) (slack.Msg, error) {
-	return castFromMapToMsg(
-		map[string]any{
-			"attachments": []any{
-				map[string]any{
-					"color": colorCrimson,
-					"blocks": []any{
-						map[string]any{
-							"type": "section",
-							"text": map[string]any{
-								"type": "mrkdwn",
-								"text": fmt.Sprintf("*InvalidArguments*\n"+
-									"%s (messageTs: `%s`)", message, messageTs),
-							},
-						},
-					},
-				},
-			},
-		},
-	)
+func SomethingIsWrong(lang i18n.Lang, messageTs string) (slack.Msg, error) {
+	return errorMessage(api.ErrorKindInternal, catalog.Sprintf(lang, msgCommonSomethingIsWrong), messageTs)
 }
 
-func SomethingIsWrong(messageTs string) slack.Msg {
-	result, _ := somethingIsWrong(messageTs)
-	return result
+// Error returns the message for err classified by api.ErrorOf.
+// Only the message of api.Error is shown, and unclassified errors are shown as SomethingIsWrong.
+func Error(lang i18n.Lang, messageTs string, err error) (slack.Msg, error) {
+	e := api.ErrorOf(err)
+	if e.Kind == api.ErrorKindInternal || e.Message == "" {
+		return SomethingIsWrong(lang, messageTs)
+	}
+	return errorMessage(e.Kind, ErrorText(lang, err), messageTs)
 }
 
-func somethingIsWrong(messageTs string) (slack.Msg, error) {
-	return castFromMapToMsg(
-		map[string]any{
-			"attachments": []any{
-				map[string]any{
-					"color": colorCrimson,
-					"blocks": []any{
-						map[string]any{
-							"type": "section",
-							"text": map[string]any{
-								"type": "mrkdwn",
-								"text": fmt.Sprintf("*InternalServerError*\n"+
-									"Please confirm to application log (messageTs: `%s`)", messageTs),
-							},
-						},
-					},
-				},
-			},
-		},
-	)
+// ErrorText returns the message of err translated into lang.
+// Unclassified errors are shown as the message of SomethingIsWrong.
+func ErrorText(lang i18n.Lang, err error) string {
+	e := api.ErrorOf(err)
+	if e.Kind == api.ErrorKindInternal || e.Message == "" {
+		return catalog.Sprintf(lang, msgCommonSomethingIsWrong)
+	}
+	return catalog.Sprintf(lang, e.Message, e.Args...)
 }
 
-func Canceled() slack.Msg {
-	result, _ := canceled()
-	return result
+func errorMessage(kind api.ErrorKind, message, messageTs string) (slack.Msg, error) {
+	return attachmentMessage(colorCrimson,
+		section(mrkdwn(fmt.Sprintf("*%s*\n"+
+			"%s (messageTs: `%s`)", kind, message, messageTs))),
+	)
 }
 
-func canceled() (slack.Msg, error) {
-	return castFromStringToMsg(fmt.Sprintf(`
-{
-	"attachments": [
-		{
-			"color": "%s",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "キャンセルされました"
-					}
-				}
-			]
-		}
-	]
-}
-`, colorHhaki))
+func Canceled(lang i18n.Lang) (slack.Msg, error) {
+	return attachmentMessage(colorHhaki,
+		section(plainText(catalog.Sprintf(lang, msgCommonCanceled))),
+	)
 }
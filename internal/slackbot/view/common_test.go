@@ -1,110 +1,103 @@
 package view
 
 import (
+	"errors"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
 func Test_showCommands(t *testing.T) {
 	t.Parallel()
+	commands := map[string]string{"hoge": "https://example.com", "fuga": ""}
 	t.Run("test", func(t *testing.T) {
-		expectedStr := replaceBackquote(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "以下のコマンドが存在します。\n<backquote><backquote><backquote>• fuga\n• <https://example.com|hoge><backquote><backquote><backquote>"
-			}
-		}
-	]
-}
-`)
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := showCommands(map[string]string{"hoge": "https://example.com", "fuga": ""})
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		assertGolden(t)(ShowCommands(i18n.Japanese, commands))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(ShowCommands(i18n.English, commands))
 	})
 }
 
 func Test_somethingIsWrong(t *testing.T) {
 	t.Parallel()
 	t.Run("test", func(t *testing.T) {
-		expectedStr := replaceBackquote(`
-{
-	"attachments": [
-		{
-			"color": "#dc143c",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "mrkdwn",
-						"text": "*InternalServerError*\nPlease confirm to application log (messageTs: <backquote>12345678<backquote>)"
-					}
-				}
-			]
-		}
-	]
-}
-`)
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := somethingIsWrong("12345678")
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		assertGolden(t)(SomethingIsWrong(i18n.English, "12345678"))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(SomethingIsWrong(i18n.Japanese, "12345678"))
 	})
 }
 
-func Test_canceled(t *testing.T) {
+func Test_invalidArguments(t *testing.T) {
 	t.Parallel()
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
+		assertGolden(t)(InvalidArguments("12345678", "args[1] must be integer"))
+	})
+	t.Run("quote in message", func(t *testing.T) {
+		assertGolden(t)(InvalidArguments("12345678", `track "A" is not found`))
+	})
+}
+
+func Test_errorOf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		lang i18n.Lang
+		err  error
+	}{
+		{
+			name: "unclassified error",
+			lang: i18n.English,
+			err:  errors.New("connection refused"),
+		},
+		{
+			name: "wrapped classified error",
+			lang: i18n.English,
+			err:  xerrors.Errorf("failed: %w", api.NotFound("label not found: hoge", errors.New("graphql"))),
+		},
+		{
+			name: "reported error",
+			lang: i18n.English,
+			err:  api.Reported(api.InvalidInput("args[1] must be integer")),
+		},
+		{
+			name: "unavailable",
+			lang: i18n.English,
+			err:  api.Unavailable("EMTEC-ECU is unavailable", errors.New("grpc")),
+		},
+		{
+			name: "translated message",
+			lang: i18n.English,
+			err:  api.NotFound(api.MsgEmtecTrackNotFound, nil, "A"),
+		},
 		{
-			"color": "#f0e68c",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "キャンセルされました"
-					}
-				}
-			]
-		}
-	]
+			name: "translated message in Japanese",
+			lang: i18n.Japanese,
+			err:  api.NotFound(api.MsgEmtecTrackNotFound, nil, "A"),
+		},
+		{
+			name: "unclassified error in Japanese",
+			lang: i18n.Japanese,
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t)(Error(tt.lang, "12345678", tt.err))
+		})
+	}
 }
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := canceled()
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
-	})
+
+func Test_canceled(t *testing.T) {
+	t.Parallel()
+	for _, lang := range i18n.Langs {
+		lang := lang
+		t.Run(string(lang), func(t *testing.T) {
+			assertGolden(t)(Canceled(lang))
+		})
+	}
 }
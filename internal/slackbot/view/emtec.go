@@ -3,6 +3,7 @@ package view
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
 	"golang.org/x/xerrors"
@@ -11,14 +12,10 @@ import (
 	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
 
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
-func EmtecListTrack(track []*pb.Track) slack.Msg {
-	result, _ := emtecListTrack(track)
-	return result
-}
-
-func emtecListTrack(pbTracks []*pb.Track) (slack.Msg, error) {
+func EmtecListTrack(pbTracks []*pb.Track) (slack.Msg, error) {
 	type trackView struct {
 		TrackId   int32  `json:"trackId"`
 		TrackName string `json:"trackName"`
@@ -32,79 +29,344 @@ func emtecListTrack(pbTracks []*pb.Track) (slack.Msg, error) {
 	}
 
 	data, err := yaml.Marshal(&track)
+	if err != nil {
+		return slack.Msg{}, xerrors.Errorf("failed to marshal tracks: %w", err)
+	}
+	return blocksMessage(section(mrkdwn("```" + string(data) + "```")))
+}
+
+func EmtecDisabled(lang i18n.Lang, trackName string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecDisabled, trackName))))
+}
+
+func EmtecEnabled(lang i18n.Lang, trackName string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecEnabled, trackName))))
+}
+
+func EmtecSelectTrack(lang i18n.Lang, signer *api.Signer, event string, tracks []*pb.Track, enabled bool) (slack.Msg, error) {
+	key, actionId := msgEmtecSelectTrackDisable, api.ActIdEmtec_SelectedTrackDisable
+	if enabled {
+		key, actionId = msgEmtecSelectTrackEnable, api.ActIdEmtec_SelectedTrackEnable
+	}
+	var options []*slack.OptionBlockObject
+	for _, track := range tracks {
+		options = append(options, option(
+			signer.Sign(api.Track{Id: track.TrackId, Name: track.TrackName, Event: event}.String()),
+			fmt.Sprintf("%s (%d)", track.TrackName, track.TrackId),
+		))
+	}
+	return attachmentMessage(colorLightGray,
+		section(plainText(withEvent(event, catalog.Sprintf(lang, key)))),
+		actions(
+			staticSelect(actionId, catalog.Sprintf(lang, msgSelectItem), options),
+			emtecCancelButton(lang),
+		),
+	)
+}
+
+func EmtecSwitchAllConfirmation(lang i18n.Lang, signer *api.Signer, event string, tracks []*pb.Track, enabled bool) (slack.Msg, error) {
+	key, actionId := msgEmtecSwitchAllDisable, api.ActIdEmtec_SwitchAllDisable
+	if enabled {
+		key, actionId = msgEmtecSwitchAllEnable, api.ActIdEmtec_SwitchAllEnable
+	}
+	var names []string
+	for _, track := range tracks {
+		names = append(names, fmt.Sprintf("• %s (%d)", track.TrackName, track.TrackId))
+	}
+	return attachmentMessage(colorLightGray,
+		section(mrkdwn(withEvent(event, catalog.Sprintf(lang, key))+"\n"+strings.Join(names, "\n"))),
+		actions(
+			button(actionId, signer.Sign(event), "OK", slack.StylePrimary),
+			emtecCancelButton(lang),
+		),
+	)
+}
+
+func EmtecSwitchedAll(lang i18n.Lang, enabled bool, succeeded, failed []string) (slack.Msg, error) {
+	key := msgEmtecSwitchedAllDisabled
+	if enabled {
+		key = msgEmtecSwitchedAllEnabled
+	}
+	lines := []string{catalog.Sprintf(lang, key, len(succeeded))}
+	for _, name := range succeeded {
+		lines = append(lines, fmt.Sprintf(":white_check_mark: %s", name))
+	}
+	for _, name := range failed {
+		lines = append(lines, fmt.Sprintf(":x: %s", name))
+	}
+	return blocksMessage(section(mrkdwn(strings.Join(lines, "\n"))))
+}
+
+func emtecCancelButton(lang i18n.Lang) *slack.ButtonBlockElement {
+	return button(api.ActIdCommon_Cancel, "", catalog.Sprintf(lang, msgCancel), slack.StyleDanger)
+}
+
+// lastSectionBlock returns the last block of msg, which has the button of api.ActIdEmtec_SceneNext
+func lastSectionBlock(msg slack.Msg) (*slack.SectionBlock, error) {
+	bs := msg.Blocks.BlockSet
+	if len(bs) == 0 {
+		return nil, xerrors.Errorf("msg.Blocks.BlockSet is empty")
+	}
+	secBlock, ok := bs[len(bs)-1].(*slack.SectionBlock)
+	if !ok {
+		return nil, xerrors.Errorf("msg.Blocks.BlockSet[-1] cannot be cast to *slack.SectionBlock")
+	}
+	if secBlock.Accessory == nil {
+		secBlock.Accessory = &slack.Accessory{}
+	}
+	return secBlock, nil
+}
+
+func EmtecMovedToNextScene(lang i18n.Lang, msg slack.Msg) (slack.Msg, error) {
+	secBlock, err := lastSectionBlock(msg)
 	if err != nil {
 		return slack.Msg{}, err
 	}
-	return castFromStringToMsg(replaceBackquote(fmt.Sprintf(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "<bq><bq><bq>%s<bq><bq><bq>"
-			}
-		}
-	]
+	secBlock.Accessory.ButtonElement = button(api.ActIdCommon_NothingToDo, "",
+		catalog.Sprintf(lang, msgEmtecSwitched), "")
+	return msg, nil
 }
-`, strings.ReplaceAll(string(data), "\n", "\\n"))))
+
+// EmtecSceneSwitchPending replaces the button of msg with the undo button.
+// The scene is switched after the undo window.
+func EmtecSceneSwitchPending(lang i18n.Lang, signer *api.Signer, msg slack.Msg, value api.SceneNext, undoWindow time.Duration) (slack.Msg, error) {
+	secBlock, err := lastSectionBlock(msg)
+	if err != nil {
+		return slack.Msg{}, err
+	}
+	secBlock.Accessory.ButtonElement = emtecSceneUndoButton(lang, signer, value, undoWindow)
+	return msg, nil
 }
 
-func EmtecDisabled(trackName string) slack.Msg {
-	result, _ := emtecDisabled(trackName)
-	return result
+func emtecSceneUndoButton(lang i18n.Lang, signer *api.Signer, value api.SceneNext, undoWindow time.Duration) *slack.ButtonBlockElement {
+	return button(api.ActIdEmtec_SceneUndo, signer.Sign(value.String()),
+		catalog.Sprintf(lang, msgEmtecUndo, int(undoWindow.Seconds())),
+		slack.StyleDanger)
 }
 
-func emtecDisabled(trackName string) (slack.Msg, error) {
-	return castFromStringToMsg(fmt.Sprintf(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "Track %s の自動切り替えを無効化しました"
+func EmtecListScene(lang i18n.Lang, signer *api.Signer, event string, track *pb.Track, scenes []*pb.Scene, confirm bool) (slack.Msg, error) {
+	var lines []string
+	for _, scene := range scenes {
+		if scene.IsCurrentProgram {
+			lines = append(lines, catalog.Sprintf(lang, msgEmtecCurrentScene, scene.SceneIndex, scene.Name))
+		} else {
+			lines = append(lines, fmt.Sprintf(":black_small_square: %d: %s", scene.SceneIndex, scene.Name))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, catalog.Sprintf(lang, msgEmtecNoScenes))
+	}
+	return blocksMessage(
+		header(withEvent(event, fmt.Sprintf("Track %s (%d)", track.TrackName, track.TrackId))),
+		sectionWithButton(mrkdwn(strings.Join(lines, "\n")),
+			emtecSceneNextButton(lang, signer, emtecSceneNextValue(event, track, scenes),
+				catalog.Sprintf(lang, msgEmtecNextScene), confirm)),
+	)
+}
+
+func EmtecNextSceneConfirmation(lang i18n.Lang, signer *api.Signer, event string, track *pb.Track, scenes []*pb.Scene, confirm bool) (slack.Msg, error) {
+	current, next := "-", "-"
+	for i, scene := range scenes {
+		if scene.IsCurrentProgram {
+			current = scene.Name
+			if i+1 < len(scenes) {
+				next = scenes[i+1].Name
 			}
 		}
-	]
+	}
+	return blocksMessage(
+		slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+			mrkdwn(withEvent(event, catalog.Sprintf(lang, msgEmtecTrack, track.TrackName))),
+			mrkdwn(catalog.Sprintf(lang, msgEmtecSceneFromTo, current, next)),
+		}, nil),
+		sectionWithButton(mrkdwn(catalog.Sprintf(lang, msgEmtecMoveToNextScene)),
+			emtecSceneNextButton(lang, signer, emtecSceneNextValue(event, track, scenes),
+				catalog.Sprintf(lang, msgEmtecSwitch), confirm)),
+	)
 }
-`, trackName))
+
+// CurrentSceneIndex returns the index of the scene on air (-1 if none)
+func CurrentSceneIndex(scenes []*pb.Scene) int32 {
+	for _, scene := range scenes {
+		if scene.IsCurrentProgram {
+			return scene.SceneIndex
+		}
+	}
+	return -1
 }
 
-func EmtecEnabled(trackName string) slack.Msg {
-	result, _ := emtecEnabled(trackName)
-	return result
+func emtecSceneNextValue(event string, track *pb.Track, scenes []*pb.Scene) api.SceneNext {
+	return api.SceneNext{
+		Track:      api.Track{Id: track.TrackId, Name: track.TrackName, Event: event},
+		SceneIndex: CurrentSceneIndex(scenes),
+	}
 }
 
-func emtecEnabled(trackName string) (slack.Msg, error) {
-	return castFromStringToMsg(fmt.Sprintf(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "Track %s の自動切り替えを有効化しました"
-			}
+// emtecSceneNextButton returns the button handled by api.ActIdEmtec_SceneNext.
+// The button must be the accessory of the last block (refer to EmtecMovedToNextScene).
+func emtecSceneNextButton(lang i18n.Lang, signer *api.Signer, value api.SceneNext, text string, confirm bool) *slack.ButtonBlockElement {
+	b := button(api.ActIdEmtec_SceneNext, signer.Sign(value.String()), text, slack.StylePrimary)
+	if confirm {
+		b.Confirm = emtecSceneNextConfirm(lang, value.Name)
+	}
+	return b
+}
+
+func emtecSceneNextConfirm(lang i18n.Lang, trackName string) *slack.ConfirmationBlockObject {
+	return slack.NewConfirmationBlockObject(
+		plainText(catalog.Sprintf(lang, msgEmtecNextSceneConfirmTitle)),
+		plainText(catalog.Sprintf(lang, msgEmtecNextSceneConfirmText, trackName)),
+		plainText(catalog.Sprintf(lang, msgOk)), plainText(catalog.Sprintf(lang, msgCancel)))
+}
+
+// EmtecDashboardTrack is the state of a track shown in the dashboard
+type EmtecDashboardTrack struct {
+	Track  *pb.Track
+	Scenes []*pb.Scene
+	// Confirm shows the confirm dialog on switching the scene
+	Confirm bool
+	// UndoWindow is positive if switching the scene is pending,
+	// when the undo button is shown instead of the button to switch
+	UndoWindow time.Duration
+}
+
+// CurrentScene returns the name of the scene on air ("-" if none)
+func (t EmtecDashboardTrack) CurrentScene() string {
+	for _, scene := range t.Scenes {
+		if scene.IsCurrentProgram {
+			return scene.Name
 		}
-	]
+	}
+	return "-"
 }
-`, trackName))
+
+// EmtecDashboard returns the dashboard of tracks. All values are signed again
+// on every render, so refreshing the dashboard extends their expiry.
+func EmtecDashboard(lang i18n.Lang, signer *api.Signer, event string, tracks []EmtecDashboardTrack, updatedBy string, updatedAt time.Time) (slack.Msg, error) {
+	blocks := []slack.Block{header(withEvent(event, catalog.Sprintf(lang, msgEmtecDashboard)))}
+	for _, t := range tracks {
+		value := signer.Sign(api.Track{Id: t.Track.TrackId, Name: t.Track.TrackName, Event: event}.String())
+		sceneNext := button(api.ActIdEmtec_DashboardSceneNext,
+			signer.Sign(emtecSceneNextValue(event, t.Track, t.Scenes).String()),
+			catalog.Sprintf(lang, msgEmtecNextScene), "")
+		if t.Confirm {
+			sceneNext.Confirm = emtecSceneNextConfirm(lang, t.Track.TrackName)
+		}
+		if t.UndoWindow > 0 {
+			sceneNext = emtecSceneUndoButton(lang, signer, emtecSceneNextValue(event, t.Track, t.Scenes), t.UndoWindow)
+		}
+		automation, toggleText, toggleStyle := ":red_circle: OFF", catalog.Sprintf(lang, msgEmtecEnable), slack.StylePrimary
+		if t.Track.Enabled {
+			automation, toggleText, toggleStyle = ":large_green_circle: ON", catalog.Sprintf(lang, msgEmtecDisable), slack.StyleDanger
+		}
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			sectionWithButton(
+				mrkdwn(catalog.Sprintf(lang, msgEmtecDashboardTrack,
+					t.Track.TrackName, t.Track.TrackId, t.Track.ObsHost, automation)),
+				button(api.ActIdEmtec_DashboardToggleAutomation, value, toggleText, toggleStyle)),
+			sectionWithButton(
+				mrkdwn(catalog.Sprintf(lang, msgEmtecDashboardScene, t.CurrentScene())),
+				sceneNext),
+		)
+	}
+	lastUpdated := catalog.Sprintf(lang, msgEmtecDashboardUpdated, updatedAt.Format("15:04:05 MST"))
+	if updatedBy != "" {
+		lastUpdated = catalog.Sprintf(lang, msgEmtecDashboardUpdatedBy, updatedAt.Format("15:04:05 MST"), updatedBy)
+	}
+	blocks = append(blocks,
+		slack.NewDividerBlock(),
+		slack.NewContextBlock("", mrkdwn(lastUpdated)),
+		actions(button(api.ActIdEmtec_DashboardRefresh, signer.Sign(event), catalog.Sprintf(lang, msgEmtecRefresh), "")),
+	)
+	return blocksMessage(blocks...)
 }
 
-func EmtecMovedToNextScene(msg slack.Msg) (slack.Msg, error) {
-	bs := &msg.Blocks.BlockSet
-	secBlock, ok := (*bs)[len(*bs)-1].(*slack.SectionBlock)
-	if !ok {
-		return slack.Msg{}, xerrors.Errorf("msg.Blocks.BlockSet[-1] cannot be cast to *slack.SectionBlock")
+// EmtecChangeKind is the kind of EmtecChange
+type EmtecChangeKind int
+
+const (
+	EmtecTrackAdded EmtecChangeKind = iota
+	EmtecTrackRemoved
+	EmtecAutomationEnabled
+	EmtecAutomationDisabled
+	EmtecSceneChanged
+)
+
+// EmtecChange is the change of a track detected by polling
+type EmtecChange struct {
+	Kind  EmtecChangeKind
+	Track string
+	// From and To are the scenes before and after EmtecSceneChanged
+	From, To string
+}
+
+func (c EmtecChange) text(lang i18n.Lang) string {
+	switch c.Kind {
+	case EmtecTrackAdded:
+		return catalog.Sprintf(lang, msgEmtecChangeTrackAdded, c.Track)
+	case EmtecTrackRemoved:
+		return catalog.Sprintf(lang, msgEmtecChangeTrackRemoved, c.Track)
+	case EmtecAutomationEnabled:
+		return catalog.Sprintf(lang, msgEmtecChangeEnabled, c.Track)
+	case EmtecAutomationDisabled:
+		return catalog.Sprintf(lang, msgEmtecChangeDisabled, c.Track)
+	default:
+		return catalog.Sprintf(lang, msgEmtecChangeScene, c.Track, c.From, c.To)
 	}
-	secBlock.Accessory.ButtonElement = &slack.ButtonBlockElement{
-		Type:     slack.METButton,
-		ActionID: api.ActIdCommon_NothingToDo,
-		Text: &slack.TextBlockObject{
-			Type: "plain_text",
-			Text: ":white_check_mark: Switched",
-		},
+}
+
+func EmtecChanges(lang i18n.Lang, event string, changes []EmtecChange) (slack.Msg, error) {
+	lines := []string{catalog.Sprintf(lang, msgEmtecChanged)}
+	for _, change := range changes {
+		lines = append(lines, "• "+withEvent(event, change.text(lang)))
 	}
-	return msg, nil
+	return blocksMessage(section(mrkdwn(strings.Join(lines, "\n"))))
+}
+
+// EmtecAutomationSwitchedBy is posted to the thread of the dashboard
+func EmtecAutomationSwitchedBy(lang i18n.Lang, trackName string, enabled bool, userId string) (slack.Msg, error) {
+	key := msgEmtecDisabledBy
+	if enabled {
+		key = msgEmtecEnabledBy
+	}
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, key, trackName, userId))))
+}
+
+// EmtecSceneSwitchedBy is posted to the thread of the dashboard
+func EmtecSceneSwitchedBy(lang i18n.Lang, trackName, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecSceneSwitchedBy, trackName, userId))))
+}
+
+// EmtecSwitchPushed is posted to the thread of the message whose switching button was pushed
+func EmtecSwitchPushed(lang i18n.Lang, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecSwitchPushed, userId))))
+}
+
+// EmtecSceneSwitchIgnored is posted if the scene had been switched before the click
+func EmtecSceneSwitchIgnored(lang i18n.Lang, trackName, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecSwitchIgnored, trackName, userId))))
+}
+
+// EmtecSceneSwitchUndone is posted if the switch is undone in the undo window
+func EmtecSceneSwitchUndone(lang i18n.Lang, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecSwitchUndone, userId))))
+}
+
+// EmtecSceneSwitchTooLateToUndo is posted if undo is clicked after the undo window
+func EmtecSceneSwitchTooLateToUndo(lang i18n.Lang, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecUndoTooLate, userId))))
+}
+
+// EmtecSceneSwitchCanceledOnShutdown is posted if seaman is shut down in the undo window
+func EmtecSceneSwitchCanceledOnShutdown(lang i18n.Lang, userId string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(catalog.Sprintf(lang, msgEmtecSwitchCanceledOnShutdown, userId))))
+}
+
+// withEvent prefixes text with the event name if it is not empty
+func withEvent(event, text string) string {
+	if event == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s] %s", event, text)
 }
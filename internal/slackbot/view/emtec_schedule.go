@@ -0,0 +1,88 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+// EmtecScheduleItem is a pending toggle of automation
+type EmtecScheduleItem struct {
+	Id int
+	// Event is the event name (empty if only one event is configured)
+	Event string
+	// Track is the ID or the name of the track
+	Track   string
+	Enabled bool
+	At      time.Time
+	// CreatedBy is the user ID or "timetable"
+	CreatedBy string
+}
+
+// Text returns the description of the schedule in lang
+func (i EmtecScheduleItem) Text(lang i18n.Lang) string {
+	key := msgEmtecScheduleItemDisable
+	if i.Enabled {
+		key = msgEmtecScheduleItemEnable
+	}
+	createdBy := i.CreatedBy
+	if createdBy != "timetable" {
+		createdBy = fmt.Sprintf("<@%s>", createdBy)
+	}
+	return withEvent(i.Event, catalog.Sprintf(lang, key,
+		i.Id, i.At.Format("2006-01-02 15:04 MST"), i.Track, createdBy))
+}
+
+func EmtecScheduled(lang i18n.Lang, item EmtecScheduleItem) (slack.Msg, error) {
+	return emtecScheduleMessage(catalog.Sprintf(lang, msgEmtecScheduled) + "\n" + item.Text(lang))
+}
+
+func EmtecListSchedule(lang i18n.Lang, items []EmtecScheduleItem) (slack.Msg, error) {
+	lines := []string{catalog.Sprintf(lang, msgEmtecSchedulePending)}
+	for _, item := range items {
+		lines = append(lines, "• "+item.Text(lang))
+	}
+	if len(items) == 0 {
+		lines = append(lines, catalog.Sprintf(lang, msgEmtecScheduleNothing))
+	}
+	return blocksMessage(
+		section(mrkdwn(strings.Join(lines, "\n"))),
+		slack.NewContextBlock("", mrkdwn(catalog.Sprintf(lang, msgEmtecScheduleInMemory))),
+	)
+}
+
+func EmtecScheduleCanceled(lang i18n.Lang, item EmtecScheduleItem, canceledBy string) (slack.Msg, error) {
+	return emtecScheduleMessage(fmt.Sprintf("%s\n~%s~",
+		catalog.Sprintf(lang, msgEmtecScheduleCanceled, canceledBy), item.Text(lang)))
+}
+
+// EmtecScheduleExecuted shows the result of the schedule, which failed if err is not nil
+func EmtecScheduleExecuted(lang i18n.Lang, item EmtecScheduleItem, err error) (slack.Msg, error) {
+	if err != nil {
+		return emtecScheduleMessage(catalog.Sprintf(lang, msgEmtecScheduleFailed, ErrorText(lang, err)) + "\n" + item.Text(lang))
+	}
+	return emtecScheduleMessage(catalog.Sprintf(lang, msgEmtecScheduleExecuted) + "\n" + item.Text(lang))
+}
+
+// EmtecInvalidSchedule is the entry of timetable which cannot be executed
+type EmtecInvalidSchedule struct {
+	Item EmtecScheduleItem
+	Err  error
+}
+
+// EmtecTimetableRejected reports that no entry of timetable is registered because of invalid ones
+func EmtecTimetableRejected(lang i18n.Lang, invalid []EmtecInvalidSchedule) (slack.Msg, error) {
+	lines := []string{catalog.Sprintf(lang, msgEmtecTimetableRejected)}
+	for _, s := range invalid {
+		lines = append(lines, fmt.Sprintf("• %s: %s", s.Item.Text(lang), ErrorText(lang, s.Err)))
+	}
+	return emtecScheduleMessage(strings.Join(lines, "\n"))
+}
+
+func emtecScheduleMessage(text string) (slack.Msg, error) {
+	return blocksMessage(section(mrkdwn(text)))
+}
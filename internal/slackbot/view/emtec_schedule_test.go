@@ -0,0 +1,79 @@
+package view
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+func testScheduleItem() EmtecScheduleItem {
+	return EmtecScheduleItem{
+		Id: 3, Track: "A", Enabled: true, CreatedBy: "U0123",
+		At: time.Date(2023, 12, 11, 13, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+	}
+}
+
+func Test_emtecScheduled(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduled(i18n.Japanese, testScheduleItem()))
+	})
+}
+
+func Test_emtecListSchedule(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		item := testScheduleItem()
+		item2 := testScheduleItem()
+		item2.Id, item2.Event, item2.Enabled, item2.CreatedBy = 4, "cndf2023", false, "timetable"
+		assertGolden(t)(EmtecListSchedule(i18n.English, []EmtecScheduleItem{item, item2}))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assertGolden(t)(EmtecListSchedule(i18n.Japanese, nil))
+	})
+}
+
+func Test_emtecScheduleCanceled(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduleCanceled(i18n.Japanese, testScheduleItem(), "U0456"))
+	})
+}
+
+func Test_emtecScheduleExecuted(t *testing.T) {
+	t.Run("succeeded", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduleExecuted(i18n.Japanese, testScheduleItem(), nil))
+	})
+	t.Run("failed", func(t *testing.T) {
+		item := testScheduleItem()
+		item.Event = "cndf2023"
+		item.CreatedBy = "timetable"
+		assertGolden(t)(EmtecScheduleExecuted(i18n.Japanese, item, api.NotFound(api.MsgEmtecTrackNotFound, nil, "A")))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduleExecuted(i18n.English, testScheduleItem(), nil))
+	})
+	t.Run("failed in English", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduleExecuted(i18n.English, testScheduleItem(), api.NotFound(api.MsgEmtecTrackNotFound, nil, "A")))
+	})
+	t.Run("internal error", func(t *testing.T) {
+		assertGolden(t)(EmtecScheduleExecuted(i18n.English, testScheduleItem(), errors.New("connection refused")))
+	})
+}
+
+func Test_emtecTimetableRejected(t *testing.T) {
+	item := testScheduleItem()
+	item.CreatedBy = "timetable"
+	unknown := item
+	unknown.Id, unknown.Event = 2, "cndt2023"
+	invalid := []EmtecInvalidSchedule{
+		{Item: item, Err: api.NotFound(api.MsgEmtecTrackNotFound, nil, "A")},
+		{Item: unknown, Err: api.InvalidInput(api.MsgEmtecEventUnknown, "cndt2023", "cndf2023")},
+	}
+	for _, lang := range i18n.Langs {
+		lang := lang
+		t.Run(string(lang), func(t *testing.T) {
+			assertGolden(t)(EmtecTimetableRejected(lang, invalid))
+		})
+	}
+}
@@ -2,56 +2,81 @@ package view
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/slack-go/slack"
 
 	pb "github.com/cloudnativedaysjp/emtec-ecu/pkg/ws-proxy/schema"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+var (
+	testTracks = []*pb.Track{
+		{TrackId: 101, TrackName: "A", ObsHost: "https://a.example.com", Enabled: true},
+		{TrackId: 102, TrackName: "B", ObsHost: "https://b.example.com", Enabled: false},
+	}
+	testScenes = []*pb.Scene{
+		{Name: "opening", SceneIndex: 0},
+		{Name: "talk", SceneIndex: 1, IsCurrentProgram: true},
+		{Name: "closing", SceneIndex: 2},
+	}
 )
 
 func Test_emtecListTrack(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
-		expectedStr := replaceBackquote(`
-{
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "<bq><bq><bq>- enabled: true\n  obsHost: https://a.example.com\n  trackId: 101\n  trackName: A\n- enabled: false\n  obsHost: https://b.example.com\n  trackId: 102\n  trackName: B\n<bq><bq><bq>"
-			}
-		}
-	]
+		assertGolden(t)(EmtecListTrack(testTracks))
+	})
+	t.Run("quote in track name", func(t *testing.T) {
+		assertGolden(t)(EmtecListTrack([]*pb.Track{
+			{TrackId: 103, TrackName: `"C" \ D`, ObsHost: "https://c.example.com"},
+		}))
+	})
 }
-`)
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		track := []*pb.Track{
-			{
-				TrackId:   101,
-				TrackName: "A",
-				ObsHost:   "https://a.example.com",
-				Enabled:   true,
-			},
-			{
-				TrackId:   102,
-				TrackName: "B",
-				ObsHost:   "https://b.example.com",
-				Enabled:   false,
-			},
-		}
-		got, err := emtecListTrack(track)
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
+
+func Test_emtecEnabled(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecEnabled(i18n.Japanese, "A"))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(EmtecEnabled(i18n.English, "A"))
+	})
+}
+
+func Test_emtecDisabled(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecDisabled(i18n.Japanese, "A"))
+	})
+	t.Run("quote in track name", func(t *testing.T) {
+		assertGolden(t)(EmtecDisabled(i18n.English, `"A" \n`))
+	})
+}
+
+func Test_emtecSelectTrack(t *testing.T) {
+	t.Run("enable", func(t *testing.T) {
+		assertGolden(t)(EmtecSelectTrack(i18n.Japanese, testSigner, "", testTracks, true))
+	})
+	t.Run("disable with event", func(t *testing.T) {
+		assertGolden(t)(EmtecSelectTrack(i18n.Japanese, testSigner, "cndf2023", testTracks, false))
+	})
+	t.Run("no tracks", func(t *testing.T) {
+		if _, err := EmtecSelectTrack(i18n.Japanese, testSigner, "", nil, true); err == nil {
+			t.Error("error is not returned")
 		}
 	})
 }
 
+func Test_emtecSwitchAllConfirmation(t *testing.T) {
+	t.Run("enable", func(t *testing.T) {
+		assertGolden(t)(EmtecSwitchAllConfirmation(i18n.Japanese, testSigner, "", testTracks, true))
+	})
+	t.Run("disable with event", func(t *testing.T) {
+		assertGolden(t)(EmtecSwitchAllConfirmation(i18n.English, testSigner, "cndf2023", testTracks, false))
+	})
+}
+
 func Test_EmtecMovedToNextScene(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
 		inputStr := `
@@ -283,21 +308,149 @@ func Test_EmtecMovedToNextScene(t *testing.T) {
 	]
 }
 `
-		input, err := castFromStringToMsg(inputStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := EmtecMovedToNextScene(input)
+		got, err := EmtecMovedToNextScene(i18n.English, msgFromJSON(t, inputStr))
 		if err != nil {
 			t.Errorf("error = %v", err)
 			return
 		}
-		if diff := cmp.Diff(expected, got); diff != "" {
+		if diff := cmp.Diff(msgFromJSON(t, expectedStr), got); diff != "" {
 			t.Error(diff)
 		}
 	})
+	t.Run("no blocks", func(t *testing.T) {
+		if _, err := EmtecMovedToNextScene(i18n.English, slack.Msg{}); err == nil {
+			t.Error("error is not returned")
+		}
+	})
+}
+
+func Test_EmtecSceneSwitchPending(t *testing.T) {
+	for name, lang := range map[string]i18n.Lang{"test": i18n.English, "Japanese": i18n.Japanese} {
+		lang := lang
+		t.Run(name, func(t *testing.T) {
+			msg, err := EmtecListScene(lang, testSigner, "", testTracks[0], testScenes, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := api.SceneNext{Track: api.Track{Id: 101, Name: "A"}, SceneIndex: 1}
+			assertGolden(t)(EmtecSceneSwitchPending(lang, testSigner, msg, value, 10*time.Second))
+		})
+	}
+}
+
+func Test_emtecMovedToNextScene(t *testing.T) {
+	t.Run("Japanese", func(t *testing.T) {
+		msg, err := EmtecNextSceneConfirmation(i18n.Japanese, testSigner, "", &pb.Track{TrackId: 1, TrackName: "A"}, testScenes, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t)(EmtecMovedToNextScene(i18n.Japanese, msg))
+	})
+}
+
+func Test_emtecListScene(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecListScene(i18n.English, testSigner, "", &pb.Track{TrackId: 1, TrackName: "A"}, testScenes, true))
+	})
+	t.Run("no scenes", func(t *testing.T) {
+		assertGolden(t)(EmtecListScene(i18n.English, testSigner, "cndf2023", &pb.Track{TrackId: 1, TrackName: "A"}, nil, false))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(EmtecListScene(i18n.Japanese, testSigner, "", &pb.Track{TrackId: 1, TrackName: "A"}, testScenes, true))
+	})
+	t.Run("no scenes in Japanese", func(t *testing.T) {
+		assertGolden(t)(EmtecListScene(i18n.Japanese, testSigner, "cndf2023", &pb.Track{TrackId: 1, TrackName: "A"}, nil, false))
+	})
+}
+
+func Test_emtecNextSceneConfirmation(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecNextSceneConfirmation(i18n.English, testSigner, "", &pb.Track{TrackId: 1, TrackName: "A"}, testScenes, true))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(EmtecNextSceneConfirmation(i18n.Japanese, testSigner, "", &pb.Track{TrackId: 1, TrackName: "A"}, testScenes, true))
+	})
+}
+
+func Test_emtecDashboard(t *testing.T) {
+	tracks := []EmtecDashboardTrack{{
+		Track: &pb.Track{TrackId: 1, TrackName: "A", ObsHost: "https://a.example.com", Enabled: true},
+		Scenes: []*pb.Scene{
+			{Name: "opening", SceneIndex: 0},
+			{Name: "talk", SceneIndex: 1, IsCurrentProgram: true},
+		},
+		Confirm: true,
+	}}
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecDashboard(i18n.English, testSigner, "",
+			tracks, "U0000", time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC),
+		))
+	})
+	t.Run("with event", func(t *testing.T) {
+		assertGolden(t)(EmtecDashboard(i18n.English, testSigner, "cndf2023",
+			[]EmtecDashboardTrack{{Track: testTracks[1]}},
+			"", time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC),
+		))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(EmtecDashboard(i18n.Japanese, testSigner, "",
+			tracks, "U0000", time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC),
+		))
+	})
+	t.Run("pending switch", func(t *testing.T) {
+		pending := tracks[0]
+		pending.UndoWindow = 10 * time.Second
+		assertGolden(t)(EmtecDashboard(i18n.English, testSigner, "",
+			[]EmtecDashboardTrack{pending}, "U0000", time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC),
+		))
+	})
+}
+
+func Test_emtecChanges(t *testing.T) {
+	changes := []EmtecChange{
+		{Kind: EmtecAutomationDisabled, Track: "A"},
+		{Kind: EmtecTrackAdded, Track: "C"},
+		{Kind: EmtecSceneChanged, Track: "B", From: "opening", To: "talk"},
+	}
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecChanges(i18n.English, "", changes))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(EmtecChanges(i18n.Japanese, "cndf2023", changes))
+	})
+}
+
+func Test_emtecThreadNotes(t *testing.T) {
+	for _, lang := range i18n.Langs {
+		lang := lang
+		t.Run(string(lang), func(t *testing.T) {
+			notes := map[string]func() (slack.Msg, error){
+				"automation":      func() (slack.Msg, error) { return EmtecAutomationSwitchedBy(lang, "A", true, "U0000") },
+				"sceneSwitchedBy": func() (slack.Msg, error) { return EmtecSceneSwitchedBy(lang, "A", "U0000") },
+				"pushed":          func() (slack.Msg, error) { return EmtecSwitchPushed(lang, "U0000") },
+				"ignored":         func() (slack.Msg, error) { return EmtecSceneSwitchIgnored(lang, "A", "U0000") },
+				"undone":          func() (slack.Msg, error) { return EmtecSceneSwitchUndone(lang, "U0000") },
+				"tooLate":         func() (slack.Msg, error) { return EmtecSceneSwitchTooLateToUndo(lang, "U0000") },
+				"shutdown":        func() (slack.Msg, error) { return EmtecSceneSwitchCanceledOnShutdown(lang, "U0000") },
+			}
+			var texts []string
+			for _, name := range []string{"automation", "sceneSwitchedBy", "pushed", "ignored", "undone", "tooLate", "shutdown"} {
+				msg, err := notes[name]()
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				texts = append(texts, msg.Blocks.BlockSet[0].(*slack.SectionBlock).Text.Text)
+			}
+			assertGolden(t)(texts, nil)
+		})
+	}
+}
+
+func Test_emtecSwitchedAll(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(EmtecSwitchedAll(i18n.Japanese, true, []string{"A", "B"}, []string{"C"}))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(EmtecSwitchedAll(i18n.English, false, []string{"A"}, []string{"B"}))
+	})
 }
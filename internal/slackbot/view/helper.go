@@ -1,10 +1,10 @@
 package view
 
 import (
-	"encoding/json"
-	"strings"
+	"unicode/utf8"
 
 	"github.com/slack-go/slack"
+	"golang.org/x/xerrors"
 )
 
 const (
@@ -15,33 +15,210 @@ const (
 	colorHhaki       = "#f0e68c"
 )
 
-func castFromMapToMsg(m map[string]any) (slack.Msg, error) {
-	b, err := json.Marshal(m)
-	if err != nil {
+// limits of Block Kit (https://api.slack.com/reference/block-kit)
+const (
+	maxBlocksOfMessage = 50
+	maxBlocksOfModal   = 100
+	maxSectionText     = 3000
+	maxSectionFields   = 10
+	maxFieldText       = 2000
+	maxHeaderText      = 150
+	maxButtonText      = 75
+	maxButtonValue     = 2000
+	maxOptions         = 100
+	maxOptionText      = 75
+	maxOptionValue     = 150
+	maxModalTitle      = 24
+	maxMetadata        = 3000
+)
+
+func plainText(text string) *slack.TextBlockObject {
+	return &slack.TextBlockObject{Type: slack.PlainTextType, Text: text}
+}
+
+func mrkdwn(text string) *slack.TextBlockObject {
+	return &slack.TextBlockObject{Type: slack.MarkdownType, Text: text}
+}
+
+func section(text *slack.TextBlockObject) *slack.SectionBlock {
+	return slack.NewSectionBlock(text, nil, nil)
+}
+
+func sectionWithButton(text *slack.TextBlockObject, button *slack.ButtonBlockElement) *slack.SectionBlock {
+	return slack.NewSectionBlock(text, nil, slack.NewAccessory(button))
+}
+
+func header(text string) *slack.HeaderBlock {
+	return slack.NewHeaderBlock(plainText(text))
+}
+
+func actions(elements ...slack.BlockElement) *slack.ActionBlock {
+	return slack.NewActionBlock("", elements...)
+}
+
+// button returns the button which is not styled if style is empty
+func button(actionId, value, text string, style slack.Style) *slack.ButtonBlockElement {
+	b := slack.NewButtonBlockElement(actionId, value, plainText(text))
+	b.Style = style
+	return b
+}
+
+func option(value, text string) *slack.OptionBlockObject {
+	return &slack.OptionBlockObject{Text: plainText(text), Value: value}
+}
+
+func staticSelect(actionId, placeholder string, options []*slack.OptionBlockObject) *slack.SelectBlockElement {
+	return slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, plainText(placeholder), actionId, options...)
+}
+
+// blocksMessage returns the message consisting of blocks
+func blocksMessage(blocks ...slack.Block) (slack.Msg, error) {
+	if err := validateBlocks(blocks, maxBlocksOfMessage); err != nil {
 		return slack.Msg{}, err
 	}
-	var result slack.Msg
-	if err := json.Unmarshal(b, &result); err != nil {
+	return slack.Msg{Blocks: slack.Blocks{BlockSet: blocks}}, nil
+}
+
+// attachmentMessage returns the message consisting of an attachment with the color bar
+func attachmentMessage(color string, blocks ...slack.Block) (slack.Msg, error) {
+	if err := validateBlocks(blocks, maxBlocksOfMessage); err != nil {
 		return slack.Msg{}, err
 	}
-	return result, nil
+	return slack.Msg{Attachments: []slack.Attachment{{
+		Color:  color,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}}}, nil
 }
 
-func castFromStringToMsg(s string) (slack.Msg, error) {
-	var result slack.Msg
-	if err := json.Unmarshal([]byte(s), &result); err != nil {
-		return slack.Msg{}, err
+// modal validates and returns the modal
+func modal(m slack.ModalViewRequest) (slack.ModalViewRequest, error) {
+	if m.Title != nil && utf8.RuneCountInString(m.Title.Text) > maxModalTitle {
+		return slack.ModalViewRequest{}, xerrors.Errorf("title of modal is longer than %d characters", maxModalTitle)
+	}
+	if utf8.RuneCountInString(m.PrivateMetadata) > maxMetadata {
+		return slack.ModalViewRequest{}, xerrors.Errorf("private_metadata of modal is longer than %d characters", maxMetadata)
+	}
+	if err := validateBlocks(m.Blocks.BlockSet, maxBlocksOfModal); err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+	return m, nil
+}
+
+// validateBlocks checks the limits of Block Kit, which Slack rejects with invalid_blocks
+func validateBlocks(blocks []slack.Block, maxBlocks int) error {
+	if len(blocks) > maxBlocks {
+		return xerrors.Errorf("number of blocks %d exceeds %d", len(blocks), maxBlocks)
+	}
+	for i, block := range blocks {
+		if err := validateBlock(block); err != nil {
+			return xerrors.Errorf("blocks[%d] (%s): %w", i, block.BlockType(), err)
+		}
+	}
+	return nil
+}
+
+func validateBlock(block slack.Block) error {
+	switch b := block.(type) {
+	case *slack.SectionBlock:
+		if b.Text == nil && len(b.Fields) == 0 {
+			return xerrors.New("either text or fields is required")
+		}
+		if b.Text != nil {
+			if err := validateText(b.Text, maxSectionText); err != nil {
+				return xerrors.Errorf("text: %w", err)
+			}
+		}
+		if len(b.Fields) > maxSectionFields {
+			return xerrors.Errorf("number of fields %d exceeds %d", len(b.Fields), maxSectionFields)
+		}
+		for i, field := range b.Fields {
+			if err := validateText(field, maxFieldText); err != nil {
+				return xerrors.Errorf("fields[%d]: %w", i, err)
+			}
+		}
+		if b.Accessory != nil && b.Accessory.ButtonElement != nil {
+			if err := validateButton(b.Accessory.ButtonElement); err != nil {
+				return xerrors.Errorf("accessory: %w", err)
+			}
+		}
+	case *slack.HeaderBlock:
+		if err := validateText(b.Text, maxHeaderText); err != nil {
+			return xerrors.Errorf("text: %w", err)
+		}
+	case *slack.ActionBlock:
+		if b.Elements == nil {
+			return nil
+		}
+		for i, element := range b.Elements.ElementSet {
+			if err := validateElement(element); err != nil {
+				return xerrors.Errorf("elements[%d]: %w", i, err)
+			}
+		}
+	case *slack.ContextBlock:
+		for i, element := range b.ContextElements.Elements {
+			if text, ok := element.(*slack.TextBlockObject); ok {
+				if err := validateText(text, maxSectionText); err != nil {
+					return xerrors.Errorf("elements[%d]: %w", i, err)
+				}
+			}
+		}
+	case *slack.InputBlock:
+		if err := validateText(b.Label, maxFieldText); err != nil {
+			return xerrors.Errorf("label: %w", err)
+		}
+		if err := validateElement(b.Element); err != nil {
+			return xerrors.Errorf("element: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateElement(element slack.BlockElement) error {
+	switch e := element.(type) {
+	case *slack.ButtonBlockElement:
+		return validateButton(e)
+	case *slack.SelectBlockElement:
+		return validateOptions(e.Options)
+	case *slack.RadioButtonsBlockElement:
+		return validateOptions(e.Options)
 	}
-	return result, nil
+	return nil
 }
 
-func replaceBackquote(s string) string {
-	result := s
-	result = strings.ReplaceAll(result, "<backquote>", "`")
-	result = strings.ReplaceAll(result, "<bq>", "`")
-	return result
+func validateButton(b *slack.ButtonBlockElement) error {
+	if err := validateText(b.Text, maxButtonText); err != nil {
+		return xerrors.Errorf("text: %w", err)
+	}
+	if len(b.Value) > maxButtonValue {
+		return xerrors.Errorf("value is longer than %d characters", maxButtonValue)
+	}
+	return nil
 }
 
-func replaceNewLine(s string) string {
-	return strings.ReplaceAll(s, "\n", "\\n")
+func validateOptions(options []*slack.OptionBlockObject) error {
+	if len(options) == 0 {
+		return xerrors.New("options are empty")
+	}
+	if len(options) > maxOptions {
+		return xerrors.Errorf("number of options %d exceeds %d", len(options), maxOptions)
+	}
+	for i, o := range options {
+		if err := validateText(o.Text, maxOptionText); err != nil {
+			return xerrors.Errorf("options[%d]: %w", i, err)
+		}
+		if len(o.Value) > maxOptionValue {
+			return xerrors.Errorf("options[%d]: value is longer than %d characters", i, maxOptionValue)
+		}
+	}
+	return nil
+}
+
+func validateText(text *slack.TextBlockObject, max int) error {
+	if text == nil || text.Text == "" {
+		return xerrors.New("text is empty")
+	}
+	if n := utf8.RuneCountInString(text.Text); n > max {
+		return xerrors.Errorf("text is longer than %d characters (%d)", max, n)
+	}
+	return nil
 }
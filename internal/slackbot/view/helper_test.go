@@ -0,0 +1,135 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/slack-go/slack"
+
+	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// testSigner signs callback values deterministically for golden files
+var testSigner = api.NewSigner([]byte("test"), time.Hour).
+	WithNow(func() time.Time { return time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC) })
+
+// assertGolden returns the function which compares the JSON of the rendered message
+// with testdata/<test name>.golden.json, e.g. assertGolden(t)(ShowVersion()).
+// Run `go test -update` to update the golden files.
+func assertGolden(t *testing.T) func(got any, err error) {
+	return func(got any, err error) {
+		t.Helper()
+		compareGolden(t, got, err)
+	}
+}
+
+func compareGolden(t *testing.T, got any, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(got); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	path := filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden.json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run `go test -update` to create it)", err)
+	}
+	if diff := cmp.Diff(string(expected), string(b)); diff != "" {
+		t.Errorf("mismatch with %s (-want +got):\n%s", path, diff)
+	}
+}
+
+func msgFromJSON(t *testing.T, s string) slack.Msg {
+	t.Helper()
+	var msg slack.Msg
+	if err := json.Unmarshal([]byte(s), &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func Test_validateBlocks(t *testing.T) {
+	t.Parallel()
+	var options []*slack.OptionBlockObject
+	for i := 0; i < maxOptions+1; i++ {
+		options = append(options, option("v", "t"))
+	}
+	tests := []struct {
+		name    string
+		blocks  []slack.Block
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			blocks: []slack.Block{section(mrkdwn("hello")), actions(button("act", "v", "OK", ""))},
+		},
+		{
+			name:    "empty text",
+			blocks:  []slack.Block{section(mrkdwn(""))},
+			wantErr: true,
+		},
+		{
+			name:    "too long text",
+			blocks:  []slack.Block{section(mrkdwn(strings.Repeat("あ", maxSectionText+1)))},
+			wantErr: true,
+		},
+		{
+			name:   "text of the maximum length in multibyte characters",
+			blocks: []slack.Block{section(mrkdwn(strings.Repeat("あ", maxSectionText)))},
+		},
+		{
+			name:    "too long header",
+			blocks:  []slack.Block{header(strings.Repeat("a", maxHeaderText+1))},
+			wantErr: true,
+		},
+		{
+			name:    "too long button text",
+			blocks:  []slack.Block{actions(button("act", "", strings.Repeat("a", maxButtonText+1), ""))},
+			wantErr: true,
+		},
+		{
+			name:    "too many options",
+			blocks:  []slack.Block{actions(staticSelect("act", "select", options))},
+			wantErr: true,
+		},
+		{
+			name:    "no options",
+			blocks:  []slack.Block{actions(staticSelect("act", "select", nil))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateBlocks(tt.blocks, maxBlocksOfMessage); (err != nil) != tt.wantErr {
+				t.Errorf("validateBlocks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,256 +7,120 @@ import (
 	"github.com/slack-go/slack"
 
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
-func ReleaseListRepo(repoUrls []string) slack.Msg {
-	result, _ := releaseListRepo(repoUrls)
-	return result
+var releaseLevels = []string{
+	api.CallbackValueRelease_VersionMajor,
+	api.CallbackValueRelease_VersionMinor,
+	api.CallbackValueRelease_VersionPatch,
 }
 
-func releaseListRepo(repoUrls []string) (slack.Msg, error) {
+func releaseRepoOptions(signer *api.Signer, repoUrls []string) []*slack.OptionBlockObject {
 	var options []*slack.OptionBlockObject
 	for _, repoUrl := range repoUrls {
 		repo := filepath.Base(repoUrl)
 		org := filepath.Base(filepath.Dir(repoUrl))
 		options = append(options,
-			&slack.OptionBlockObject{
-				Text: &slack.TextBlockObject{
-					Type: slack.PlainTextType,
-					Text: fmt.Sprintf("%s/%s", org, repo),
-				},
-				Value: fmt.Sprintf("%s__%s", org, repo),
-			},
+			option(signer.Sign(api.OrgRepoOf(org, repo).String()), fmt.Sprintf("%s/%s", org, repo)),
 		)
 	}
-
-	return castFromMapToMsg(
-		map[string]any{
-			"attachments": []any{
-				map[string]any{
-					"color": colorLightGray,
-					"blocks": []any{
-						map[string]any{
-							"type": "section",
-							"text": map[string]any{
-								"type": "plain_text",
-								"text": "リリース対象のリポジトリを選択",
-							},
-						},
-						map[string]any{
-							"type": "actions",
-							"elements": []any{
-								map[string]any{
-									"type": "static_select",
-									"placeholder": map[string]any{
-										"type": "plain_text",
-										"text": "Select an item",
-									},
-									"action_id": api.ActIdRelease_SelectedRepository,
-									"options":   options,
-								},
-								map[string]any{
-									"type": "button",
-									"text": map[string]any{
-										"type": "plain_text",
-										"text": "Cancel",
-									},
-									"action_id": api.ActIdCommon_Cancel,
-									"style":     "danger",
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	)
+	return options
 }
 
-func ReleaseListLevel(orgRepo api.OrgRepo) slack.Msg {
-	result, _ := releaseListLevel(orgRepo)
-	return result
+func releaseCancelButton(lang i18n.Lang) *slack.ButtonBlockElement {
+	return button(api.ActIdCommon_Cancel, "", catalog.Sprintf(lang, msgCancel), slack.StyleDanger)
 }
 
-func releaseListLevel(orgRepo api.OrgRepo) (slack.Msg, error) {
-	return castFromMapToMsg(
-		map[string]any{
-			"attachments": []any{
-				map[string]any{
-					"color": colorLightGray,
-					"blocks": []any{
-						map[string]any{
-							"type": "section",
-							"text": map[string]any{
-								"text": "更新レベルを選択",
-								"type": "plain_text",
-							},
-						},
-						map[string]any{
-							"type": "actions",
-							"elements": []any{
-								map[string]any{
-									"type": "button",
-									"text": map[string]any{
-										"type": "plain_text",
-										"text": api.CallbackValueRelease_VersionMajor,
-									},
-									"action_id": api.ActIdRelease_SelectedLevelMajor,
-									"value":     orgRepo.WithLevel(api.CallbackValueRelease_VersionMajor).String(),
-								},
-								map[string]any{
-									"type": "button",
-									"text": map[string]any{
-										"text": api.CallbackValueRelease_VersionMinor,
-										"type": "plain_text",
-									},
-									"action_id": api.ActIdRelease_SelectedLevelMinor,
-									"value":     orgRepo.WithLevel(api.CallbackValueRelease_VersionMinor).String(),
-								},
-								map[string]any{
-									"type": "button",
-									"text": map[string]any{
-										"type": "plain_text",
-										"text": api.CallbackValueRelease_VersionPatch,
-									},
-									"action_id": api.ActIdRelease_SelectedLevelPatch,
-									"value":     orgRepo.WithLevel(api.CallbackValueRelease_VersionPatch).String(),
-								},
-								map[string]any{
-									"type": "button",
-									"text": map[string]any{
-										"type": "plain_text",
-										"text": "Cancel",
-									},
-									"action_id": api.ActIdCommon_Cancel,
-									"style":     "danger",
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+func ReleaseListRepo(lang i18n.Lang, signer *api.Signer, repoUrls []string) (slack.Msg, error) {
+	return attachmentMessage(colorLightGray,
+		section(plainText(catalog.Sprintf(lang, msgReleaseSelectRepository))),
+		actions(
+			staticSelect(api.ActIdRelease_SelectedRepository,
+				catalog.Sprintf(lang, msgSelectItem), releaseRepoOptions(signer, repoUrls)),
+			button(api.ActIdRelease_OpenModal, "", catalog.Sprintf(lang, msgReleaseOpenModal), ""),
+			releaseCancelButton(lang),
+		),
 	)
 }
 
-func ReleaseConfirmation(orgRepoLevel api.OrgRepoLevel) slack.Msg {
-	result, _ := releaseConfirmation(orgRepoLevel)
-	return result
-}
+// ReleaseModal is the modal for inputting all parameters of release at once
+func ReleaseModal(lang i18n.Lang, signer *api.Signer, repoUrls []string, metadata api.ReleaseModalMetadata) (slack.ModalViewRequest, error) {
+	var levels []*slack.OptionBlockObject
+	for _, level := range releaseLevels {
+		levels = append(levels, option(level, level))
+	}
+	repository := slack.NewInputBlock(api.BlockIdRelease_Repository,
+		plainText(catalog.Sprintf(lang, msgReleaseRepository)), nil,
+		staticSelect(api.BlockIdRelease_Repository,
+			catalog.Sprintf(lang, msgSelectItem), releaseRepoOptions(signer, repoUrls)))
+	level := slack.NewInputBlock(api.BlockIdRelease_Level,
+		plainText(catalog.Sprintf(lang, msgReleaseLevel)), nil,
+		slack.NewRadioButtonsBlockElement(api.BlockIdRelease_Level, levels...))
+	notesInput := slack.NewPlainTextInputBlockElement(nil, api.BlockIdRelease_Notes)
+	notesInput.Multiline = true
+	notes := slack.NewInputBlock(api.BlockIdRelease_Notes,
+		plainText(catalog.Sprintf(lang, msgReleaseNotes)), nil, notesInput)
+	notes.Optional = true
 
-func releaseConfirmation(orgRepoLevel api.OrgRepoLevel) (slack.Msg, error) {
-	org := orgRepoLevel.Org()
-	repo := orgRepoLevel.Repo()
-	level := orgRepoLevel.Level()
-	return castFromMapToMsg(map[string]any{
-		"attachments": []any{
-			map[string]any{
-				"color": colorLightGray,
-				"blocks": []any{
-					map[string]any{
-						"type": "section",
-						"text": map[string]any{
-							"type": "mrkdwn",
-							"text": fmt.Sprintf(
-								"OK? > Target: *%s/%s*, Update Level: *%s*", org, repo, level,
-							),
-						},
-					},
-					map[string]any{
-						"type": "actions",
-						"elements": []any{
-							map[string]any{
-								"type": "button",
-								"text": map[string]any{
-									"type": "plain_text",
-									"text": "OK",
-								},
-								"action_id": api.ActIdRelease_OK,
-								"value":     orgRepoLevel.String(),
-							},
-							map[string]any{
-								"type": "button",
-								"text": map[string]any{
-									"type": "plain_text",
-									"text": "Cancel",
-								},
-								"action_id": api.ActIdCommon_Cancel,
-								"style":     "danger",
-							},
-						},
-					},
-				},
-			},
-		},
+	return modal(slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      api.CallbackIdRelease_Modal,
+		PrivateMetadata: metadata.String(),
+		Title:           plainText(catalog.Sprintf(lang, msgReleaseModalTitle)),
+		Submit:          plainText(catalog.Sprintf(lang, msgReleaseCreatePr)),
+		Close:           plainText(catalog.Sprintf(lang, msgCancel)),
+		Blocks:          slack.Blocks{BlockSet: []slack.Block{repository, level, notes}},
 	})
 }
 
-func ReleaseProcessing() slack.Msg {
-	result, _ := releaseProcessing()
-	return result
+// ReleaseModalError is the response of submitting the modal, which shows message at the input of blockId
+func ReleaseModalError(blockId, message string) *slack.ViewSubmissionResponse {
+	return slack.NewErrorsViewSubmissionResponse(map[string]string{blockId: message})
 }
 
-func releaseProcessing() (slack.Msg, error) {
-	return castFromStringToMsg(fmt.Sprintf(`
-{
-	"attachments": [
-		{
-			"color": "%s",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "processing..."
-					}
-				}
-			]
-		}
-	]
+func ReleaseListLevel(lang i18n.Lang, signer *api.Signer, orgRepo api.OrgRepo) (slack.Msg, error) {
+	actionIds := map[string]string{
+		api.CallbackValueRelease_VersionMajor: api.ActIdRelease_SelectedLevelMajor,
+		api.CallbackValueRelease_VersionMinor: api.ActIdRelease_SelectedLevelMinor,
+		api.CallbackValueRelease_VersionPatch: api.ActIdRelease_SelectedLevelPatch,
+	}
+	var buttons []slack.BlockElement
+	for _, level := range releaseLevels {
+		buttons = append(buttons,
+			button(actionIds[level], signer.Sign(orgRepo.WithLevel(level).String()), level, ""))
+	}
+	buttons = append(buttons, releaseCancelButton(lang))
+	return attachmentMessage(colorLightGray,
+		section(plainText(catalog.Sprintf(lang, msgReleaseSelectLevel))),
+		actions(buttons...),
+	)
 }
-`, colorLightGray))
+
+func ReleaseConfirmation(lang i18n.Lang, signer *api.Signer, orgRepoLevel api.OrgRepoLevel) (slack.Msg, error) {
+	return attachmentMessage(colorLightGray,
+		section(mrkdwn(catalog.Sprintf(lang, msgReleaseConfirm,
+			orgRepoLevel.Org(), orgRepoLevel.Repo(), orgRepoLevel.Level()))),
+		actions(
+			button(api.ActIdRelease_OK, signer.Sign(orgRepoLevel.String()), "OK", ""),
+			releaseCancelButton(lang),
+		),
+	)
 }
 
-func ReleaseDisplayPrLink(orgRepoLevel api.OrgRepoLevel, prNumber int) slack.Msg {
-	result, _ := releaseDisplayPrLink(orgRepoLevel, prNumber)
-	return result
+func ReleaseProcessing(lang i18n.Lang) (slack.Msg, error) {
+	return attachmentMessage(colorLightGray,
+		section(plainText(catalog.Sprintf(lang, msgReleaseProcessing))),
+	)
 }
 
-func releaseDisplayPrLink(orgRepoLevel api.OrgRepoLevel, prNumber int) (slack.Msg, error) {
-	org := orgRepoLevel.Org()
-	repo := orgRepoLevel.Repo()
-	level := orgRepoLevel.Level()
-	return castFromMapToMsg(map[string]any{
-		"attachments": []any{
-			map[string]any{
-				"color": colorDeepSkyBlue,
-				"blocks": []any{
-					map[string]any{
-						"type": "section",
-						"fields": []any{
-							map[string]any{
-								"type": "mrkdwn",
-								"text": fmt.Sprintf("Target: *%s/%s*", org, repo),
-							},
-							map[string]any{
-								"type": "mrkdwn",
-								"text": fmt.Sprintf("Update Level: *%s*", level),
-							},
-						},
-					},
-					map[string]any{
-						"type": "divider",
-					},
-					map[string]any{
-						"type": "section",
-						"text": map[string]any{
-							"type": "mrkdwn",
-							"text": fmt.Sprintf(":github: <%s>", orgRepoLevel.PullRequestUrl(prNumber)),
-						},
-					},
-				},
-			},
-		},
-	})
+func ReleaseDisplayPrLink(lang i18n.Lang, orgRepoLevel api.OrgRepoLevel, prNumber int) (slack.Msg, error) {
+	return attachmentMessage(colorDeepSkyBlue,
+		slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+			mrkdwn(catalog.Sprintf(lang, msgReleaseTarget, orgRepoLevel.Org(), orgRepoLevel.Repo())),
+			mrkdwn(catalog.Sprintf(lang, msgReleaseUpdateLevel, orgRepoLevel.Level())),
+		}, nil),
+		slack.NewDividerBlock(),
+		section(mrkdwn(fmt.Sprintf(":github: <%s>", orgRepoLevel.PullRequestUrl(prNumber)))),
+	)
 }
@@ -4,304 +4,82 @@ import (
 	"testing"
 
 	"github.com/cloudnativedaysjp/seaman/internal/slackbot/api"
-	"github.com/google/go-cmp/cmp"
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
 )
 
 func Test_releaseListRepo(t *testing.T) {
+	repoUrls := []string{
+		"https://github.com/cloudnativedaysjp/dreamkast",
+		"https://github.com/cloudnativedaysjp/dreamkast-ui",
+	}
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
-		{
-			"color": "#d3d3d3",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "リリース対象のリポジトリを選択"
-					}
-				},
-				{
-					"type": "actions",
-					"elements": [
-						{
-							"type": "static_select",
-							"placeholder": {
-								"type": "plain_text",
-								"text": "Select an item"
-							},
-							"action_id": "release_selected_repo",
-							"options": [
-								{
-									"text": {
-										"type": "plain_text",
-										"text": "cloudnativedaysjp/dreamkast"
-									},
-									"value": "cloudnativedaysjp__dreamkast"
-								},
-								{
-									"text": {
-										"type": "plain_text",
-										"text": "cloudnativedaysjp/dreamkast-ui"
-									},
-									"value": "cloudnativedaysjp__dreamkast-ui"
-								}
-							]
-						},
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "Cancel"
-							},
-							"action_id": "common_cancel",
-							"style": "danger"
-						}
-					]
-				}
-			]
-		}
-	]
+		assertGolden(t)(ReleaseListRepo(i18n.Japanese, testSigner, repoUrls))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(ReleaseListRepo(i18n.English, testSigner, repoUrls))
+	})
 }
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := releaseListRepo([]string{
-			"https://github.com/cloudnativedaysjp/dreamkast",
-			"https://github.com/cloudnativedaysjp/dreamkast-ui",
-		})
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
+
+func Test_releaseModal(t *testing.T) {
+	repoUrls := []string{
+		"https://github.com/cloudnativedaysjp/dreamkast",
+	}
+	metadata := api.ReleaseModalMetadata{ChannelId: "C0123456789", MessageTs: "1234567890.123456"}
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(ReleaseModal(i18n.Japanese, testSigner, repoUrls, metadata))
+	})
+	t.Run("English", func(t *testing.T) {
+		assertGolden(t)(ReleaseModal(i18n.English, testSigner, repoUrls, metadata))
+	})
+	for _, lang := range i18n.Langs {
+		// the limit of the title of modals
+		if title := catalog.Sprintf(lang, msgReleaseModalTitle); len([]rune(title)) > 24 {
+			t.Errorf("title of modal in %s is longer than 24 characters: %s", lang, title)
 		}
+	}
+}
+
+func Test_releaseModalError(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		assertGolden(t)(ReleaseModalError(api.BlockIdRelease_Repository,
+			"NotFound: cloudnativedaysjp/dreamkast is not a release target"), nil)
 	})
 }
 
 func Test_releaseListLevel(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
-		{
-			"color": "#d3d3d3",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "更新レベルを選択"
-					}
-				},
-				{
-					"type": "actions",
-					"elements": [
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "release/major"
-							},
-							"action_id": "release_selected_level_major",
-							"value": "cloudnativedaysjp__dreamkast__release/major"
-						},
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "release/minor"
-							},
-							"action_id": "release_selected_level_minor",
-							"value": "cloudnativedaysjp__dreamkast__release/minor"
-						},
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "release/patch"
-							},
-							"action_id": "release_selected_level_patch",
-							"value": "cloudnativedaysjp__dreamkast__release/patch"
-						},
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "Cancel"
-							},
-							"action_id": "common_cancel",
-							"style": "danger"
-						}
-					]
-				}
-			]
-		}
-	]
-}
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		orgRepo, _ := api.NewOrgRepo("cloudnativedaysjp__dreamkast")
-		got, err := releaseListLevel(orgRepo)
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		orgRepo := api.OrgRepoOf("cloudnativedaysjp", "dreamkast")
+		assertGolden(t)(ReleaseListLevel(i18n.Japanese, testSigner, orgRepo))
 	})
 }
 
 func Test_releaseConfirmation(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
-		{
-			"color": "#d3d3d3",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "mrkdwn",
-						"text": "OK? > Target: *cloudnativedaysjp/dreamkast*, Update Level: *release/major*"
-					}
-				},
-				{
-					"type": "actions",
-					"elements": [
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "OK"
-							},
-							"action_id": "release_ok",
-							"value": "cloudnativedaysjp__dreamkast__release/major"
-						},
-						{
-							"type": "button",
-							"text": {
-								"type": "plain_text",
-								"text": "Cancel"
-							},
-							"action_id": "common_cancel",
-							"style": "danger"
-						}
-					]
-				}
-			]
-		}
-	]
-}
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		orgRepoLevel, _ := api.NewOrgRepoLevel("cloudnativedaysjp__dreamkast__release/major")
-		got, err := releaseConfirmation(orgRepoLevel)
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		orgRepoLevel := api.OrgRepoOf("cloudnativedaysjp", "dreamkast").WithLevel(api.CallbackValueRelease_VersionMajor)
+		assertGolden(t)(ReleaseConfirmation(i18n.Japanese, testSigner, orgRepoLevel))
+	})
+	t.Run("English", func(t *testing.T) {
+		orgRepoLevel := api.OrgRepoOf("cloudnativedaysjp", "dreamkast").WithLevel(api.CallbackValueRelease_VersionMajor)
+		assertGolden(t)(ReleaseConfirmation(i18n.English, testSigner, orgRepoLevel))
 	})
 }
 
 func Test_releaseProcessing(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
-		{
-			"color": "#d3d3d3",
-			"blocks": [
-				{
-					"type": "section",
-					"text": {
-						"type": "plain_text",
-						"text": "processing..."
-					}
-				}
-			]
-		}
-	]
-}
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got, err := releaseProcessing()
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		assertGolden(t)(ReleaseProcessing(i18n.English))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(ReleaseProcessing(i18n.Japanese))
 	})
 }
 
 func Test_releaseDisplayPrLink(t *testing.T) {
 	t.Run("test", func(t *testing.T) {
-		expectedStr := `
-{
-	"attachments": [
-		{
-			"color": "#00bfff",
-			"blocks": [
-				{
-					"type": "section",
-					"fields": [
-						{
-							"type": "mrkdwn",
-							"text": "Target: *cloudnativedaysjp/dreamkast*"
-						},
-						{
-							"type": "mrkdwn",
-							"text": "Update Level: *release/patch*"
-						}
-					]
-				},
-				{
-					"type": "divider"
-				},
-				{
-					"type": "section",
-					"text": {
-						"type": "mrkdwn",
-						"text": ":github: <https://github.com/cloudnativedaysjp/dreamkast/pull/1416>"
-					}
-				}
-			]
-		}
-	]
-}
-`
-		expected, err := castFromStringToMsg(expectedStr)
-		if err != nil {
-			t.Fatal(err)
-		}
-		orgRepoLevel, _ := api.NewOrgRepoLevel("cloudnativedaysjp__dreamkast__release/patch")
-		got, err := releaseDisplayPrLink(orgRepoLevel, 1416)
-		if err != nil {
-			t.Errorf("error = %v", err)
-			return
-		}
-		if diff := cmp.Diff(expected, got); diff != "" {
-			t.Error(diff)
-		}
+		orgRepoLevel := api.OrgRepoOf("cloudnativedaysjp", "dreamkast").WithLevel(api.CallbackValueRelease_VersionPatch)
+		assertGolden(t)(ReleaseDisplayPrLink(i18n.English, orgRepoLevel, 1416))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		orgRepoLevel := api.OrgRepoOf("cloudnativedaysjp", "dreamkast").WithLevel(api.CallbackValueRelease_VersionPatch)
+		assertGolden(t)(ReleaseDisplayPrLink(i18n.Japanese, orgRepoLevel, 1416))
 	})
 }
@@ -0,0 +1,15 @@
+package view
+
+import (
+	"github.com/slack-go/slack"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+func WebhookReplayed(lang i18n.Lang, deliveryId string, enqueued bool) (slack.Msg, error) {
+	text := catalog.Sprintf(lang, msgWebhookReplayed, deliveryId)
+	if !enqueued {
+		text = catalog.Sprintf(lang, msgWebhookReplayedNoCommand, deliveryId)
+	}
+	return blocksMessage(section(mrkdwn(text)))
+}
@@ -0,0 +1,23 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/i18n"
+)
+
+func Test_webhookReplayed(t *testing.T) {
+	t.Parallel()
+	t.Run("enqueued", func(t *testing.T) {
+		assertGolden(t)(WebhookReplayed(i18n.English, "72d3162e-cc78-11e3-81ab-4c9367dc0958", true))
+	})
+	t.Run("no command", func(t *testing.T) {
+		assertGolden(t)(WebhookReplayed(i18n.English, "72d3162e-cc78-11e3-81ab-4c9367dc0958", false))
+	})
+	t.Run("Japanese", func(t *testing.T) {
+		assertGolden(t)(WebhookReplayed(i18n.Japanese, "72d3162e-cc78-11e3-81ab-4c9367dc0958", true))
+	})
+	t.Run("no command in Japanese", func(t *testing.T) {
+		assertGolden(t)(WebhookReplayed(i18n.Japanese, "72d3162e-cc78-11e3-81ab-4c9367dc0958", false))
+	})
+}
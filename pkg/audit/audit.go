@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+const defaultCapacity = 100
+
+// Source is where the action was requested from
+const (
+	SourceSlack  = "slack"
+	SourceGitHub = "github"
+	SourceAdmin  = "admin"
+	SourceSystem = "system"
+)
+
+// Outcome is the result of the action
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	// OutcomeIgnored means the action was accepted but did nothing (e.g. stale click)
+	OutcomeIgnored = "ignored"
+)
+
+// Entry is a record of an operational action.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	// Actor is Slack user ID, GitHub login or the name of the component
+	Actor  string `json:"actor"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	// Outcome is one of "success", "failure" and "ignored"
+	Outcome string `json:"outcome"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// OutcomeOf returns OutcomeFailure if err is not nil, otherwise OutcomeSuccess.
+func OutcomeOf(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// String returns the entry in one line.
+func (e Entry) String() string {
+	s := fmt.Sprintf("%s %s:%s %s %s => %s",
+		e.Time.Format(time.RFC3339), e.Source, e.Actor, e.Action, e.Target, e.Outcome)
+	if e.Detail != "" {
+		s += fmt.Sprintf(" (%s)", e.Detail)
+	}
+	return s
+}
+
+// Sink is the destination of entries.
+type Sink interface {
+	Write(ctx context.Context, e Entry) error
+}
+
+// Closer is implemented by sinks which flush or release something on shutdown.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Logger records entries to sinks and keeps recent entries in memory.
+// All methods of nil Logger do nothing.
+type Logger struct {
+	sinks []Sink
+
+	mu   sync.RWMutex
+	buf  []Entry
+	next int
+}
+
+// New returns Logger keeping up to capacity recent entries.
+func New(capacity int) *Logger {
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
+	return &Logger{buf: make([]Entry, 0, capacity)}
+}
+
+// WithSink adds the destination of entries.
+func (l *Logger) WithSink(sink Sink) *Logger {
+	l.sinks = append(l.sinks, sink)
+	return l
+}
+
+// Record records the entry. Failures of sinks are only logged
+// because auditing must not prevent the action.
+func (l *Logger) Record(ctx context.Context, e Entry) {
+	if l == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	if len(l.buf) < cap(l.buf) {
+		l.buf = append(l.buf, e)
+	} else {
+		l.buf[l.next] = e
+		l.next = (l.next + 1) % cap(l.buf)
+	}
+	l.mu.Unlock()
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, e); err != nil {
+			log.FromContext(ctx).Warn(fmt.Sprintf("failed to write audit log: %v", err))
+		}
+	}
+}
+
+// Close closes the sinks after flushing the entries being written. Entries recorded
+// after Close are kept in memory only.
+func (l *Logger) Close(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	var errs []error
+	for _, sink := range l.sinks {
+		if c, ok := sink.(Closer); ok {
+			if err := c.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Recent returns up to n entries from newest to oldest.
+func (l *Logger) Recent(n int) []Entry {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var result []Entry
+	for i := 0; i < len(l.buf) && len(result) < n; i++ {
+		// next is the oldest entry (or 0 until buf is full)
+		result = append(result, l.buf[(l.next-1-i+len(l.buf))%len(l.buf)])
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogger_Recent(t *testing.T) {
+	t.Parallel()
+	actions := func(entries []Entry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.Action)
+		}
+		return result
+	}
+	t.Run("recent entries are returned from newest to oldest", func(t *testing.T) {
+		l := New(3)
+		for _, action := range []string{"a", "b"} {
+			l.Record(context.Background(), Entry{Action: action})
+		}
+		if diff := cmp.Diff([]string{"b", "a"}, actions(l.Recent(10))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("oldest entry is overwritten when capacity is exceeded", func(t *testing.T) {
+		l := New(3)
+		for _, action := range []string{"a", "b", "c", "d", "e"} {
+			l.Record(context.Background(), Entry{Action: action})
+		}
+		if diff := cmp.Diff([]string{"e", "d", "c"}, actions(l.Recent(10))); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"e", "d"}, actions(l.Recent(2))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("nil Logger does nothing", func(t *testing.T) {
+		var l *Logger
+		l.Record(context.Background(), Entry{Action: "a"})
+		if got := l.Recent(10); got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+	})
+}
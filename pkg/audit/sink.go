@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/slack-go/slack"
+	"golang.org/x/xerrors"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+//
+// fileSink
+//
+
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink returns Sink appending entries to the file as JSON Lines.
+func NewFileSink(path string) (Sink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open audit log file: %w", err)
+	}
+	return &fileSink{file: f}, nil
+}
+
+func (s *fileSink) Close(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Close(); err != nil {
+		return xerrors.Errorf("failed to close audit log file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) Write(_ context.Context, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal entry: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return xerrors.Errorf("failed to write entry: %w", err)
+	}
+	return nil
+}
+
+//
+// slackSink
+//
+
+// SlackPoster is satisfied by the Slack client of seaman
+type SlackPoster interface {
+	PostMessage(ctx context.Context, channel string, msg slack.Msg) error
+}
+
+// slackSinkBufferSize is the number of entries waiting to be posted
+const slackSinkBufferSize = 100
+
+type slackSink struct {
+	client    SlackPoster
+	channelId string
+
+	mu      sync.Mutex
+	closed  bool
+	entries chan slackEntry
+	done    chan struct{}
+}
+
+type slackEntry struct {
+	ctx   context.Context
+	entry Entry
+}
+
+// NewSlackSink returns Sink posting entries to the Slack channel. Entries are posted
+// in background so that a slow channel does not delay the action, and dropped
+// if too many entries are waiting. Close posts the waiting entries.
+func NewSlackSink(client SlackPoster, channelId string) Sink {
+	s := &slackSink{
+		client:    client,
+		channelId: channelId,
+		entries:   make(chan slackEntry, slackSinkBufferSize),
+		done:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *slackSink) Write(ctx context.Context, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return xerrors.Errorf("sink is closed, dropped entry: %s", e)
+	}
+	select {
+	case s.entries <- slackEntry{context.WithoutCancel(ctx), e}:
+		return nil
+	default:
+		return xerrors.Errorf("buffer is full, dropped entry: %s", e)
+	}
+}
+
+func (s *slackSink) run() {
+	defer close(s.done)
+	for e := range s.entries {
+		if err := s.client.PostMessage(e.ctx, s.channelId, slack.Msg{Text: e.entry.String()}); err != nil {
+			log.FromContext(e.ctx).Warn(fmt.Sprintf("failed to write audit log: failed to post entry: %v", err))
+		}
+	}
+}
+
+// Close waits until the waiting entries are posted
+func (s *slackSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.entries)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return xerrors.Errorf("failed to post waiting entries: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/slack-go/slack"
+)
+
+type fakePoster struct {
+	mu     sync.Mutex
+	block  chan struct{}
+	posted []string
+}
+
+func (p *fakePoster) PostMessage(_ context.Context, _ string, msg slack.Msg) error {
+	<-p.block
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.posted = append(p.posted, msg.Text)
+	return nil
+}
+
+func TestSlackSink(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2023, 12, 11, 12, 0, 0, 0, time.UTC)
+	entry := func(action string) Entry {
+		return Entry{Time: at, Source: SourceSlack, Actor: "U0000", Action: action, Outcome: OutcomeSuccess}
+	}
+	t.Run("entries are posted in background and flushed on Close", func(t *testing.T) {
+		p := &fakePoster{block: make(chan struct{})}
+		s := NewSlackSink(p, "C0000")
+		for _, action := range []string{"a", "b"} {
+			// Write does not wait for the slow channel
+			if err := s.Write(context.Background(), entry(action)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		close(p.block)
+		if err := s.(Closer).Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{entry("a").String(), entry("b").String()}
+		if diff := cmp.Diff(want, p.posted); diff != "" {
+			t.Error(diff)
+		}
+		if err := s.Write(context.Background(), entry("c")); err == nil {
+			t.Error("Write() after Close() error = nil")
+		}
+	})
+	t.Run("entries are dropped if the buffer is full", func(t *testing.T) {
+		p := &fakePoster{block: make(chan struct{})}
+		s := NewSlackSink(p, "C0000")
+		var dropped int
+		for i := 0; i < slackSinkBufferSize+2; i++ {
+			if err := s.Write(context.Background(), entry("a")); err != nil {
+				dropped++
+			}
+		}
+		// one entry may be taken by the goroutine waiting for the channel
+		if dropped != 1 && dropped != 2 {
+			t.Errorf("dropped = %d", dropped)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := s.(Closer).Close(ctx); err == nil {
+			t.Error("Close() error = nil while entries are waiting")
+		}
+		close(p.block)
+	})
+}
@@ -0,0 +1,86 @@
+package cosme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/webhooks/v6/github"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+)
+
+// DefaultAuthorAssociations is allowed to run commands which has no Authorization.
+var DefaultAuthorAssociations = []string{"OWNER", "COLLABORATOR", "CONTRIBUTOR", "MEMBER"}
+
+// Authorization restricts who can run a command and where.
+// Empty fields mean no restriction (except AuthorAssociations, which falls back to DefaultAuthorAssociations).
+type Authorization struct {
+	// AuthorAssociations is the list of allowed author associations (e.g. "OWNER", "MEMBER")
+	AuthorAssociations []string
+	// Teams is the list of "<org>/<team-slug>". The author must be a member of any of them.
+	Teams []string
+	// Repositories is the list of "<org>/<repo>" on which the command can be run
+	Repositories []string
+}
+
+// TeamMembershipChecker checks team membership via GitHub API.
+// githubapi.GitHubApiClient satisfies this interface.
+type TeamMembershipChecker interface {
+	IsTeamMember(ctx context.Context, org, teamSlug, user string) (bool, error)
+}
+
+// WithAuthorization sets Authorization of the command.
+func (h *handler) WithAuthorization(command string, auth Authorization) *handler {
+	h.authorizations[normalizeCommandName(command)] = auth
+	return h
+}
+
+// WithTeamMembershipChecker sets the checker used for Authorization.Teams.
+func (h *handler) WithTeamMembershipChecker(checker TeamMembershipChecker) *handler {
+	h.teamChecker = checker
+	return h
+}
+
+// authorizeStatically checks the author association and the repository,
+// which can be done without calling GitHub API.
+func (h *handler) authorizeStatically(command string, payload github.IssueCommentPayload) (bool, string) {
+	auth := h.authorizations[command]
+	associations := auth.AuthorAssociations
+	if len(associations) == 0 {
+		associations = DefaultAuthorAssociations
+	}
+	if !utils.Contains(associations, payload.Comment.AuthorAssociation) {
+		return false, fmt.Sprintf("author association %s is not allowed", payload.Comment.AuthorAssociation)
+	}
+	if len(auth.Repositories) != 0 && !utils.Contains(auth.Repositories, payload.Repository.FullName) {
+		return false, fmt.Sprintf("repository %s is not allowed", payload.Repository.FullName)
+	}
+	return true, ""
+}
+
+// authorizeTeams checks the team membership of the author.
+func (h *handler) authorizeTeams(ctx context.Context, command string, payload github.IssueCommentPayload) (bool, string, error) {
+	auth := h.authorizations[command]
+	if len(auth.Teams) == 0 {
+		return true, "", nil
+	}
+	if h.teamChecker == nil {
+		return false, "", fmt.Errorf("TeamMembershipChecker is not set")
+	}
+	user := payload.Comment.User.Login
+	for _, team := range auth.Teams {
+		org, slug, ok := strings.Cut(team, "/")
+		if !ok {
+			return false, "", fmt.Errorf("invalid team %s: must be <org>/<team-slug>", team)
+		}
+		member, err := h.teamChecker.IsTeamMember(ctx, org, slug, user)
+		if err != nil {
+			return false, "", fmt.Errorf("IsTeamMember failed: %w", err)
+		}
+		if member {
+			return true, "", nil
+		}
+	}
+	return false, fmt.Sprintf("user %s is not a member of %s", user, strings.Join(auth.Teams, ", ")), nil
+}
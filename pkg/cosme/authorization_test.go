@@ -0,0 +1,45 @@
+package cosme
+
+import (
+	"testing"
+
+	"github.com/go-playground/webhooks/v6/github"
+)
+
+func Test_authorizeStatically(t *testing.T) {
+	t.Parallel()
+	h, err := New(nil, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.WithAuthorization("/SEPARATE", Authorization{
+		AuthorAssociations: []string{"OWNER"},
+		Repositories:       []string{"org/allowed"},
+	})
+	newPayload := func(association, repo string) github.IssueCommentPayload {
+		var payload github.IssueCommentPayload
+		payload.Comment.AuthorAssociation = association
+		payload.Repository.FullName = repo
+		return payload
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		payload  github.IssueCommentPayload
+		expected bool
+	}{
+		{"default associations", "/HELP", newPayload("CONTRIBUTOR", "org/any"), true},
+		{"default associations (NONE)", "/HELP", newPayload("NONE", "org/any"), false},
+		{"allowed", "/SEPARATE", newPayload("OWNER", "org/allowed"), true},
+		{"association is not allowed", "/SEPARATE", newPayload("MEMBER", "org/allowed"), false},
+		{"repository is not allowed", "/SEPARATE", newPayload("OWNER", "org/other"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := h.authorizeStatically(tt.command, tt.payload); got != tt.expected {
+				t.Errorf("got = %v, expected = %v (reason: %s)", got, tt.expected, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package cosme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/webhooks/v6/github"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
+)
+
+// HandlerFunc is the handler of a command
+type HandlerFunc func(ctx context.Context, payload github.IssueCommentPayload, args Args) error
+
+// CommandSpec describes a command for parsing its arguments and generating help.
+type CommandSpec struct {
+	// Name is the command name such as "/SEPARATE" (case-insensitive)
+	Name        string
+	Description string
+	Flags       []FlagSpec
+}
+
+// FlagSpec describes a flag such as `--envs=dev,prod`.
+type FlagSpec struct {
+	Name        string
+	Description string
+	// Default is used if the flag is not specified
+	Default string
+	// IsBool means the flag takes no value such as `--dry-run`
+	IsBool bool
+	// Choices is the list of allowed values. Each of comma-separated values is checked.
+	Choices []string
+}
+
+// Usage returns the usage such as "/SEPARATE [--envs=dev,prod]".
+func (s CommandSpec) Usage() string {
+	usage := []string{s.Name}
+	for _, f := range s.Flags {
+		if f.IsBool {
+			usage = append(usage, fmt.Sprintf("[--%s]", f.Name))
+		} else {
+			usage = append(usage, fmt.Sprintf("[--%s=%s]", f.Name, f.Default))
+		}
+	}
+	return strings.Join(usage, " ")
+}
+
+func (s CommandSpec) flag(name string) (FlagSpec, bool) {
+	for _, f := range s.Flags {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return FlagSpec{}, false
+}
+
+// Args is parsed arguments of a command.
+type Args struct {
+	Positional []string          `json:"positional"`
+	Flags      map[string]string `json:"flags"`
+}
+
+// Flag returns the value of the flag (or its default).
+func (a Args) Flag(name string) string {
+	return a.Flags[name]
+}
+
+// FlagList returns the value of the flag split by comma.
+func (a Args) FlagList(name string) []string {
+	var result []string
+	for _, v := range strings.Split(a.Flags[name], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// BoolFlag returns true if the boolean flag is specified.
+func (a Args) BoolFlag(name string) bool {
+	return a.Flags[name] == "true"
+}
+
+type command struct {
+	spec    CommandSpec
+	handler HandlerFunc
+}
+
+func normalizeCommandName(name string) string {
+	return strings.ToUpper(name)
+}
+
+// WithCommand registers the command without description.
+func (h *handler) WithCommand(command string, handler HandlerFunc) *handler {
+	return h.WithCommandSpec(CommandSpec{Name: command}, handler)
+}
+
+// WithCommandSpec registers the command with its spec.
+func (h *handler) WithCommandSpec(spec CommandSpec, handler HandlerFunc) *handler {
+	h.commands[normalizeCommandName(spec.Name)] = command{spec, handler}
+	return h
+}
+
+// Commands returns specs of registered commands ordered by name.
+func (h *handler) Commands() []CommandSpec {
+	var specs []CommandSpec
+	for _, c := range h.commands {
+		specs = append(specs, c.spec)
+	}
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// invocation is a command found in the comment body.
+type invocation struct {
+	name string
+	args []string
+}
+
+// findInvocations returns commands written at the beginning of any line in
+// the body. Lines in quotes or code blocks are ignored.
+func (h *handler) findInvocations(body string) []invocation {
+	var result []invocation
+	inCodeBlock := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || strings.HasPrefix(line, ">") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		name := normalizeCommandName(fields[0])
+		if _, ok := h.commands[name]; ok {
+			result = append(result, invocation{name, fields[1:]})
+		}
+	}
+	return result
+}
+
+// parseArgs parses args according to spec.
+func parseArgs(spec CommandSpec, args []string) (Args, error) {
+	result := Args{Flags: make(map[string]string)}
+	for _, f := range spec.Flags {
+		if f.Default != "" {
+			result.Flags[f.Name] = f.Default
+		}
+	}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			result.Positional = append(result.Positional, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		f, ok := spec.flag(name)
+		if !ok {
+			return Args{}, fmt.Errorf("unknown flag: --%s", name)
+		}
+		switch {
+		case f.IsBool && hasValue:
+			return Args{}, fmt.Errorf("flag --%s does not take a value", name)
+		case f.IsBool:
+			value = "true"
+		case !hasValue || value == "":
+			return Args{}, fmt.Errorf("flag --%s requires a value (--%s=VALUE)", name, name)
+		}
+		for _, v := range strings.Split(value, ",") {
+			if len(f.Choices) != 0 && !utils.Contains(f.Choices, v) {
+				return Args{}, fmt.Errorf("invalid value of --%s: %s (choices: %s)", name, v, strings.Join(f.Choices, ", "))
+			}
+		}
+		result.Flags[name] = value
+	}
+	return result, nil
+}
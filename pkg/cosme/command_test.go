@@ -0,0 +1,78 @@
+package cosme
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_findInvocations(t *testing.T) {
+	t.Parallel()
+	h, err := New(nil, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.WithCommand("/HELP", nil).WithCommand("/SEPARATE", nil)
+
+	t.Run("test", func(t *testing.T) {
+		body := "LGTM\n" +
+			"/separate --envs=dev\n" +
+			"> /HELP\n" +
+			"```\n/HELP\n```\n" +
+			"  /Help"
+		expected := []invocation{
+			{"/SEPARATE", []string{"--envs=dev"}},
+			{"/HELP", []string{}},
+		}
+		got := h.findInvocations(body)
+		if diff := cmp.Diff(expected, got, cmp.AllowUnexported(invocation{})); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func Test_parseArgs(t *testing.T) {
+	t.Parallel()
+	spec := CommandSpec{
+		Name: "/SEPARATE",
+		Flags: []FlagSpec{
+			{Name: "envs", Default: "dev,prod", Choices: []string{"dev", "prod"}},
+			{Name: "dry-run", IsBool: true},
+		},
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected Args
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			args:     nil,
+			expected: Args{Flags: map[string]string{"envs": "dev,prod"}},
+		},
+		{
+			name:     "flags and positional",
+			args:     []string{"--envs=prod", "--dry-run", "foo"},
+			expected: Args{Positional: []string{"foo"}, Flags: map[string]string{"envs": "prod", "dry-run": "true"}},
+		},
+		{name: "unknown flag", args: []string{"--foo=bar"}, wantErr: true},
+		{name: "invalid choice", args: []string{"--envs=dev,stg"}, wantErr: true},
+		{name: "missing value", args: []string{"--envs"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(spec, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -1,37 +1,61 @@
 package cosme
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-playground/webhooks/v6/github"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 
 	"github.com/cloudnativedaysjp/seaman/pkg/log"
-	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 )
 
+var tracer = otel.Tracer("github.com/cloudnativedaysjp/seaman/pkg/cosme")
+
 const (
-	channelLength                     = 8
-	timeoutFromEnqueuedUntilProceeded = 20 * time.Second
+	defaultWorkers       = 4
+	defaultMaxRetries    = 3
+	defaultRetryInterval = 5 * time.Second
 )
 
-type issueCommentHandler func(ctx context.Context, payload github.IssueCommentPayload, args []string) error
-
 type handler struct {
-	c        chan data
-	commands map[string]issueCommentHandler
+	commands map[string]command
 	log      *slog.Logger
 	hook     *github.Webhook
-}
 
-type data struct {
-	payload github.IssueCommentPayload
-	ctx     context.Context
-	cancel  context.CancelFunc
+	scheduler     *scheduler
+	store         Store
+	workers       int
+	maxRetries    int
+	retryInterval time.Duration
+	isTransient   func(error) bool
+	feedback      Feedback
+	deliveries    *deliveryRing
+	observer      Observer
+	// stopped is closed when all workers exit
+	stopped chan struct{}
+	// feedbackCtx is the context of feedback sent outside the workers (reactions
+	// on receipt and errors of arguments), which is canceled if Shutdown times out
+	feedbackCtx    context.Context
+	cancelFeedback context.CancelFunc
+	feedbackMu     sync.Mutex
+	feedbackWg     sync.WaitGroup
+	feedbackClosed bool
+
+	authorizations map[string]Authorization
+	teamChecker    TeamMembershipChecker
 }
 
 func New(logger *slog.Logger, secret string) (*handler, error) {
@@ -42,74 +66,354 @@ func New(logger *slog.Logger, secret string) (*handler, error) {
 	if err != nil {
 		return nil, err
 	}
+	feedbackCtx, cancelFeedback := context.WithCancel(context.Background())
 	h := &handler{
-		make(chan data, channelLength),
-		make(map[string]issueCommentHandler),
-		logger.With("package", "cosme"),
-		hook,
+		commands:      make(map[string]command),
+		log:           logger.With("package", "cosme"),
+		hook:          hook,
+		scheduler:     newScheduler(),
+		store:         NewMemoryStore(),
+		workers:       defaultWorkers,
+		maxRetries:    defaultMaxRetries,
+		retryInterval: defaultRetryInterval,
+		isTransient:   IsTransient,
+		deliveries:    newDeliveryRing(defaultDeliveryCapacity),
+		stopped:       make(chan struct{}),
+
+		feedbackCtx:    feedbackCtx,
+		cancelFeedback: cancelFeedback,
+
+		authorizations: make(map[string]Authorization),
 	}
-	go h.RunBackground()
 	return h, nil
 }
 
-func (h *handler) WithCommand(command string, handler issueCommentHandler) *handler {
-	h.commands[command] = handler
+// WithWorkers sets the number of jobs processed concurrently.
+// Jobs for the same repository are always processed one by one.
+func (h *handler) WithWorkers(n int) *handler {
+	if n > 0 {
+		h.workers = n
+	}
 	return h
 }
 
-func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+// WithStore sets Store used for persisting queued jobs.
+func (h *handler) WithStore(store Store) *handler {
+	h.store = store
+	return h
+}
+
+// WithRetry sets the retry policy for jobs failed with transient errors.
+// If isTransient is nil, IsTransient is used.
+func (h *handler) WithRetry(maxRetries int, interval time.Duration, isTransient func(error) bool) *handler {
+	h.maxRetries = maxRetries
+	h.retryInterval = interval
+	if isTransient != nil {
+		h.isTransient = isTransient
+	}
+	return h
+}
+
+// Observer is called with the result of each attempt of commands (e.g. for metrics)
+type Observer func(command string, duration time.Duration, err error)
+
+// WithObserver sets Observer called after each attempt of commands.
+func (h *handler) WithObserver(observer Observer) *handler {
+	h.observer = observer
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	// get payload
-	payloadRaw, err := h.hook.Parse(r, github.IssueCommentEvent)
+	payload, err := h.parse(r.Context(), r.Header, body)
 	if err != nil {
 		return
 	}
+	deliveryId := r.Header.Get("X-GitHub-Delivery")
+	h.deliveries.add(Delivery{
+		ID:         deliveryId,
+		Event:      r.Header.Get("X-GitHub-Event"),
+		ReceivedAt: time.Now(),
+		Header:     r.Header.Clone(),
+		Body:       body,
+	})
+
+	if _, err := h.enqueue(payload, jobId(deliveryId)); err != nil {
+		h.log.Error(fmt.Sprintf("failed to persist job: %v", err), log.KeyDetail, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// parse verifies the signature and returns the payload of IssueComment event.
+func (h *handler) parse(ctx context.Context, header http.Header, body []byte) (github.IssueCommentPayload, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return github.IssueCommentPayload{}, err
+	}
+	r.Header = header.Clone()
+	payloadRaw, err := h.hook.Parse(r, github.IssueCommentEvent)
+	if err != nil {
+		return github.IssueCommentPayload{}, err
+	}
 	payload, ok := payloadRaw.(github.IssueCommentPayload)
 	if !ok {
-		return
+		return github.IssueCommentPayload{}, fmt.Errorf("payload is not IssueCommentPayload")
 	}
+	return payload, nil
+}
+
+// enqueue queues the command in the payload. It returns false if the payload
+// contains no command to be processed.
+func (h *handler) enqueue(payload github.IssueCommentPayload, id string) (bool, error) {
 	if payload.Action != "created" {
-		return
+		return false, nil
 	}
 
-	// if the user who send the IssueComment is unauthorized, skip
-	roles := []string{"OWNER", "COLLABORATOR", "CONTRIBUTOR", "MEMBER"}
-	if !utils.Contains(roles, payload.Comment.AuthorAssociation) {
-		h.log.Info("unauthorized the user who send the IssueComment")
+	invocations := h.findInvocations(payload.Comment.Body)
+	if len(invocations) == 0 {
+		return false, nil
+	}
+
+	var jobs []Job
+	for i, inv := range invocations {
+		// if the user who send the IssueComment is unauthorized, skip
+		if ok, reason := h.authorizeStatically(inv.name, payload); !ok {
+			h.log.Info(fmt.Sprintf("unauthorized the user who send the IssueComment: %s", reason),
+				"command", inv.name, "user", payload.Comment.User.Login)
+			continue
+		}
+		spec := h.commands[inv.name].spec
+		args, err := parseArgs(spec, inv.args)
+		if err != nil {
+			h.log.Info(fmt.Sprintf("invalid arguments: %v", err), "command", inv.name)
+			h.goFeedback(func(ctx context.Context) {
+				h.reportInvalidArguments(ctx, payload, spec, err)
+			})
+			continue
+		}
+		jobId := id
+		if i != 0 {
+			jobId = fmt.Sprintf("%s-%d", id, i)
+		}
+		jobs = append(jobs, Job{
+			ID:         jobId,
+			Command:    inv.name,
+			Args:       args,
+			Payload:    payload,
+			EnqueuedAt: time.Now(),
+		})
+	}
+	if len(jobs) == 0 {
+		return false, nil
+	}
+	for _, j := range jobs {
+		if err := h.store.Put(j); err != nil {
+			return false, err
+		}
+	}
+	for _, j := range jobs {
+		h.scheduler.push(j)
+	}
+	h.goFeedback(func(ctx context.Context) {
+		h.react(ctx, payload, ReactionEyes)
+	})
+	return true, nil
+}
+
+// RunBackground restores jobs persisted in Store and processes queued jobs
+// until ctx is done or Shutdown is called. It must be called after all
+// commands are registered.
+//
+// When ctx is done, queued jobs and retries are left in Store so that they are
+// restored on next launch. In-flight handlers are never interrupted.
+func (h *handler) RunBackground(ctx context.Context) {
+	jobs, err := h.store.List()
+	if err != nil {
+		h.log.Error(fmt.Sprintf("failed to restore jobs: %v", err), log.KeyDetail, err)
+	}
+	for _, j := range jobs {
+		h.scheduler.push(j)
+	}
+	if len(jobs) != 0 {
+		h.log.Info(fmt.Sprintf("restored %d job(s)", len(jobs)))
+	}
+
+	var wg sync.WaitGroup
+	for range h.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				j, ok := h.scheduler.pop()
+				if !ok {
+					return
+				}
+				h.process(ctx, j)
+				h.scheduler.done(j)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(h.stopped)
+	}()
+	select {
+	case <-ctx.Done():
+		h.scheduler.close()
+		<-h.stopped
+		h.cancelFeedback()
+	case <-h.stopped:
+	}
+}
+
+// Shutdown stops accepting new jobs and waits until queued jobs and feedback
+// are processed. If ctx is done before that, it returns ctx.Err(), cancels the
+// feedback and the remaining jobs are left in Store (cancel the context of
+// RunBackground to stop them).
+func (h *handler) Shutdown(ctx context.Context) error {
+	h.scheduler.close()
+	h.feedbackMu.Lock()
+	h.feedbackClosed = true
+	h.feedbackMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		<-h.stopped
+		h.feedbackWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.cancelFeedback()
+		return ctx.Err()
+	}
+}
+
+// goFeedback runs f in background, which Shutdown waits for.
+// f is not run after Shutdown is called.
+func (h *handler) goFeedback(f func(ctx context.Context)) {
+	h.feedbackMu.Lock()
+	defer h.feedbackMu.Unlock()
+	if h.feedbackClosed {
 		return
 	}
+	h.feedbackWg.Add(1)
+	go func() {
+		defer h.feedbackWg.Done()
+		f(h.feedbackCtx)
+	}()
+}
 
-	if len(strings.Fields(payload.Comment.Body)) == 0 {
-		h.log.Info("invalid command: args.length == 0")
+func (h *handler) process(ctx context.Context, j Job) {
+	logger := h.log.With("correlationId", j.ID, "command", j.Command, "repo", j.key())
+	c, ok := h.commands[j.Command]
+	if !ok {
+		h.deadLetter(logger, j, fmt.Errorf("command %s is not registered", j.Command))
+		h.reportFailure(ctx, j)
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFromEnqueuedUntilProceeded)
-	h.c <- data{payload, ctx, cancel}
 
-	w.WriteHeader(http.StatusOK)
-}
+	// check team membership here because it requires GitHub API
+	authorized, reason, err := h.authorizeTeams(ctx, j.Command, j.Payload)
+	if err != nil {
+		h.deadLetter(logger, j, err)
+		h.reportFailure(ctx, j)
+		return
+	} else if !authorized {
+		logger.Info(fmt.Sprintf("unauthorized the user who send the IssueComment: %s", reason))
+		if err := h.store.Delete(j.ID); err != nil {
+			logger.Warn(fmt.Sprintf("failed to delete job from store: %v", err))
+		}
+		h.react(ctx, j.Payload, ReactionConfused)
+		return
+	}
 
-func (h *handler) RunBackground() {
-	for d := range h.c {
+	for {
 		select {
-		case <-d.ctx.Done():
-			h.log.Info("context has already exceeded")
-			continue
+		case <-ctx.Done():
+			// leave the job in Store so that it is restored on next launch
+			return
 		default:
-			d.cancel()
 		}
 
-		ctx := context.Background()
-		ctx = log.IntoContext(ctx, h.log)
-		commandAndArgs := strings.Fields(d.payload.Comment.Body)
-
-		for registeredCommand, handler := range h.commands {
-			if commandAndArgs[0] == registeredCommand {
-				if err := handler(ctx, d.payload, commandAndArgs[1:]); err != nil {
-					h.log.Error(fmt.Sprintf("internal server error: %v", err),
-						log.KeyDetail, err)
-					return
-				}
+		j.Attempts++
+		// in-flight handler is not interrupted even if ctx is done
+		err := h.attempt(log.IntoContext(context.WithoutCancel(ctx), logger), c, j)
+		if err == nil {
+			if err := h.store.Delete(j.ID); err != nil {
+				logger.Warn(fmt.Sprintf("failed to delete job from store: %v", err))
 			}
+			h.react(context.WithoutCancel(ctx), j.Payload, ReactionRocket)
+			return
+		}
+		j.LastError = err.Error()
+		if !h.isTransient(err) || j.Attempts > h.maxRetries {
+			h.deadLetter(logger, j, err)
+			h.reportFailure(context.WithoutCancel(ctx), j)
+			return
+		}
+
+		interval := backoff(h.retryInterval, j.Attempts)
+		logger.Warn(fmt.Sprintf("transient error, retry after %v: %v", interval, err),
+			"attempts", j.Attempts)
+		if err := h.store.Put(j); err != nil {
+			logger.Warn(fmt.Sprintf("failed to update job in store: %v", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
 		}
 	}
 }
+
+// attempt calls the handler of the command in the span and records the result
+func (h *handler) attempt(ctx context.Context, c command, j Job) error {
+	ctx, span := tracer.Start(ctx, "cosme "+j.Command, trace.WithAttributes(
+		attribute.String("cosme.correlation_id", j.ID),
+		attribute.String("github.repository", j.key()),
+		attribute.Int("github.pull_request", int(j.Payload.Issue.Number)),
+		attribute.String("github.user", j.Payload.Comment.User.Login),
+		attribute.Int("cosme.attempts", j.Attempts),
+	))
+	defer span.End()
+
+	start := time.Now()
+	err := c.handler(ctx, j.Payload, j.Args)
+	if h.observer != nil {
+		h.observer(j.Command, time.Since(start), err)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (h *handler) deadLetter(logger *slog.Logger, j Job, err error) {
+	logger.Error(fmt.Sprintf("internal server error: %v", err),
+		log.KeyDetail, err, "attempts", j.Attempts)
+	if err := h.store.DeadLetter(j); err != nil {
+		logger.Warn(fmt.Sprintf("failed to move job to dead-letter: %v", err))
+	}
+}
+
+// jobId returns ID of the job. X-GitHub-Delivery (UUID) is used if valid
+// because the ID is also used as a filename by fileStore.
+func jobId(deliveryId string) string {
+	if deliveryId != "" && strings.Trim(deliveryId, "0123456789abcdefABCDEF-") == "" {
+		return deliveryId
+	}
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
+}
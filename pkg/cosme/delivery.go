@@ -0,0 +1,106 @@
+package cosme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultDeliveryCapacity = 100
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// Delivery is a raw webhook delivery received from GitHub.
+type Delivery struct {
+	ID         string
+	Event      string
+	ReceivedAt time.Time
+	Header     http.Header
+	Body       []byte
+}
+
+// deliveryRing keeps recent deliveries up to its capacity.
+type deliveryRing struct {
+	mu   sync.RWMutex
+	buf  []Delivery
+	next int
+}
+
+func newDeliveryRing(capacity int) *deliveryRing {
+	return &deliveryRing{buf: make([]Delivery, 0, capacity)}
+}
+
+func (r *deliveryRing) add(d Delivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cap(r.buf) == 0 {
+		return
+	}
+	if len(r.buf) < cap(r.buf) {
+		r.buf = append(r.buf, d)
+		return
+	}
+	r.buf[r.next] = d
+	r.next = (r.next + 1) % cap(r.buf)
+}
+
+// list returns deliveries from newest to oldest.
+func (r *deliveryRing) list() []Delivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]Delivery, 0, len(r.buf))
+	for i := range len(r.buf) {
+		idx := (r.next - 1 - i + 2*len(r.buf)) % len(r.buf)
+		result = append(result, r.buf[idx])
+	}
+	return result
+}
+
+func (r *deliveryRing) get(id string) (Delivery, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, d := range r.buf {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Delivery{}, false
+}
+
+// WithDeliveryCapacity sets the number of recent deliveries kept for Replay.
+func (h *handler) WithDeliveryCapacity(capacity int) *handler {
+	if capacity >= 0 {
+		h.deliveries = newDeliveryRing(capacity)
+	}
+	return h
+}
+
+// Deliveries returns recent deliveries from newest to oldest.
+func (h *handler) Deliveries() []Delivery {
+	return h.deliveries.list()
+}
+
+// Replay re-runs the stored delivery through the same pipeline as ServeHTTP.
+// It returns false if the delivery contains no command to be processed.
+func (h *handler) Replay(ctx context.Context, deliveryId string) (bool, error) {
+	d, ok := h.deliveries.get(deliveryId)
+	if !ok {
+		return false, ErrDeliveryNotFound
+	}
+	payload, err := h.parse(ctx, d.Header, d.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse delivery: %w", err)
+	}
+	id := jobId("")
+	enqueued, err := h.enqueue(payload, id)
+	if err != nil {
+		return false, err
+	}
+	if enqueued {
+		h.log.Info(fmt.Sprintf("replayed delivery %s", deliveryId), "correlationId", id)
+	}
+	return enqueued, nil
+}
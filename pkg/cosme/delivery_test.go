@@ -0,0 +1,27 @@
+package cosme
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_deliveryRing(t *testing.T) {
+	t.Parallel()
+	t.Run("oldest delivery is overwritten when capacity is exceeded", func(t *testing.T) {
+		r := newDeliveryRing(2)
+		for _, id := range []string{"1", "2", "3"} {
+			r.add(Delivery{ID: id})
+		}
+		var got []string
+		for _, d := range r.list() {
+			got = append(got, d.ID)
+		}
+		if diff := cmp.Diff([]string{"3", "2"}, got); diff != "" {
+			t.Error(diff)
+		}
+		if _, ok := r.get("1"); ok {
+			t.Error(`delivery "1" must be overwritten`)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package cosme
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-playground/webhooks/v6/github"
+)
+
+// Reactions which is added to IssueComment
+const (
+	ReactionEyes     = "EYES"
+	ReactionRocket   = "ROCKET"
+	ReactionConfused = "CONFUSED"
+)
+
+// Feedback is used for notifying the status of commands on GitHub.
+// githubapi.GitHubApiClient satisfies this interface.
+type Feedback interface {
+	AddReaction(ctx context.Context, subjectId, content string) error
+	CreateIssueComment(ctx context.Context, org, repo string, prNum int, body string) error
+}
+
+// WithFeedback enables reactions and error comments on GitHub:
+// "eyes" on receipt, "rocket" on success and "confused" with an error comment on failure.
+func (h *handler) WithFeedback(feedback Feedback) *handler {
+	h.feedback = feedback
+	return h
+}
+
+func (h *handler) react(ctx context.Context, payload github.IssueCommentPayload, reaction string) {
+	if h.feedback == nil {
+		return
+	}
+	if err := h.feedback.AddReaction(ctx, payload.Comment.NodeID, reaction); err != nil {
+		h.log.Warn(fmt.Sprintf("failed to add reaction %s: %v", reaction, err))
+	}
+}
+
+func (h *handler) reportFailure(ctx context.Context, j Job) {
+	if h.feedback == nil {
+		return
+	}
+	h.react(ctx, j.Payload, ReactionConfused)
+
+	// the error itself is not shown because it may contain sensitive information
+	body := fmt.Sprintf(":warning: `%s` failed.\n\n"+
+		"Please ask the administrator to confirm the application log with the following correlation ID.\n"+
+		"* Correlation ID: `%s`\n"+
+		"* Attempts: %d\n", j.Command, j.ID, j.Attempts)
+	if err := h.feedback.CreateIssueComment(ctx,
+		j.Payload.Repository.Owner.Login, j.Payload.Repository.Name, int(j.Payload.Issue.Number), body,
+	); err != nil {
+		h.log.Warn(fmt.Sprintf("failed to post error comment: %v", err))
+	}
+}
+
+func (h *handler) reportInvalidArguments(ctx context.Context, payload github.IssueCommentPayload, spec CommandSpec, err error) {
+	if h.feedback == nil {
+		return
+	}
+	h.react(ctx, payload, ReactionConfused)
+
+	body := fmt.Sprintf(":warning: invalid arguments: %v\n\nUsage: `%s`\n", err, spec.Usage())
+	if err := h.feedback.CreateIssueComment(ctx,
+		payload.Repository.Owner.Login, payload.Repository.Name, int(payload.Issue.Number), body,
+	); err != nil {
+		h.log.Warn(fmt.Sprintf("failed to post error comment: %v", err))
+	}
+}
@@ -0,0 +1,191 @@
+package cosme
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-playground/webhooks/v6/github"
+	"golang.org/x/xerrors"
+)
+
+// Job is a unit of work: a single command in a single IssueComment.
+type Job struct {
+	ID         string                     `json:"id"`
+	Command    string                     `json:"command"`
+	Args       Args                       `json:"args"`
+	Payload    github.IssueCommentPayload `json:"payload"`
+	Attempts   int                        `json:"attempts"`
+	EnqueuedAt time.Time                  `json:"enqueuedAt"`
+	LastError  string                     `json:"lastError,omitempty"`
+}
+
+// key is used to serialize jobs targeting the same repository.
+func (j Job) key() string {
+	return j.Payload.Repository.FullName
+}
+
+// Store persists queued jobs so that they survive restarts.
+type Store interface {
+	// Put saves the Job as pending (overwrites if exists).
+	Put(j Job) error
+	// Delete removes the Job from pending.
+	Delete(id string) error
+	// List returns all pending jobs ordered by EnqueuedAt.
+	List() ([]Job, error)
+	// DeadLetter moves the Job from pending to dead-letter.
+	DeadLetter(j Job) error
+}
+
+//
+// memoryStore
+//
+
+type memoryStore struct {
+	mu          sync.Mutex
+	pending     map[string]Job
+	deadLetters []Job
+}
+
+// NewMemoryStore returns Store which keeps jobs only in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{pending: make(map[string]Job)}
+}
+
+func (s *memoryStore) Put(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[j.ID] = j
+	return nil
+}
+
+func (s *memoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+	return nil
+}
+
+func (s *memoryStore) List() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, 0, len(s.pending))
+	for _, j := range s.pending {
+		jobs = append(jobs, j)
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *memoryStore) DeadLetter(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, j.ID)
+	s.deadLetters = append(s.deadLetters, j)
+	return nil
+}
+
+//
+// fileStore
+//
+
+const (
+	fileStorePendingDir    = "pending"
+	fileStoreDeadLetterDir = "deadletter"
+)
+
+type fileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore returns Store which keeps each Job as a JSON file under dir.
+func NewFileStore(dir string) (Store, error) {
+	for _, sub := range []string{fileStorePendingDir, fileStoreDeadLetterDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o750); err != nil {
+			return nil, xerrors.Errorf("os.MkdirAll failed: %w", err)
+		}
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Put(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(fileStorePendingDir, j)
+}
+
+func (s *fileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(fileStorePendingDir, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("os.Remove failed: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) List() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(filepath.Join(s.dir, fileStorePendingDir))
+	if err != nil {
+		return nil, xerrors.Errorf("os.ReadDir failed: %w", err)
+	}
+	var jobs []Job
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, fileStorePendingDir, entry.Name()))
+		if err != nil {
+			return nil, xerrors.Errorf("os.ReadFile failed: %w", err)
+		}
+		var j Job
+		if err := json.Unmarshal(data, &j); err != nil {
+			return nil, xerrors.Errorf("json.Unmarshal failed (%s): %w", entry.Name(), err)
+		}
+		jobs = append(jobs, j)
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *fileStore) DeadLetter(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(fileStoreDeadLetterDir, j); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(fileStorePendingDir, j.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("os.Remove failed: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) path(sub, id string) string {
+	return filepath.Join(s.dir, sub, id+".json")
+}
+
+// write saves the Job atomically by renaming a temporary file.
+func (s *fileStore) write(sub string, j Job) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return xerrors.Errorf("json.Marshal failed: %w", err)
+	}
+	tmp := s.path(sub, j.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return xerrors.Errorf("os.WriteFile failed: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(sub, j.ID)); err != nil {
+		return xerrors.Errorf("os.Rename failed: %w", err)
+	}
+	return nil
+}
+
+func sortJobs(jobs []Job) {
+	sort.SliceStable(jobs, func(i, k int) bool { return jobs[i].EnqueuedAt.Before(jobs[k].EnqueuedAt) })
+}
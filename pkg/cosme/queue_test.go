@@ -0,0 +1,191 @@
+package cosme
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/webhooks/v6/github"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestJob(id, repo string, enqueuedAt time.Time) Job {
+	var payload github.IssueCommentPayload
+	payload.Repository.FullName = repo
+	return Job{ID: id, Command: "/HELP", Payload: payload, EnqueuedAt: enqueuedAt}
+}
+
+func Test_fileStore(t *testing.T) {
+	t.Parallel()
+	t.Run("test", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		j1 := newTestJob("1", "org/a", now.Add(time.Second))
+		j2 := newTestJob("2", "org/b", now)
+		j3 := newTestJob("3", "org/c", now.Add(2*time.Second))
+		for _, j := range []Job{j1, j2, j3} {
+			if err := store.Put(j); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := store.Delete(j1.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.DeadLetter(j3); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]Job{j2}, got); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func Test_scheduler(t *testing.T) {
+	t.Parallel()
+	t.Run("jobs for the same repository are not handed out simultaneously", func(t *testing.T) {
+		now := time.Now()
+		s := newScheduler()
+		s.push(newTestJob("1", "org/a", now))
+		s.push(newTestJob("2", "org/a", now))
+		s.push(newTestJob("3", "org/b", now))
+
+		var got []string
+		j1, _ := s.pop()
+		j3, _ := s.pop()
+		got = append(got, j1.ID, j3.ID)
+		s.done(j1)
+		j2, _ := s.pop()
+		got = append(got, j2.ID)
+		s.done(j2)
+		s.done(j3)
+		s.close()
+		if _, ok := s.pop(); ok {
+			t.Error("pop() must return false after close")
+		}
+		if diff := cmp.Diff([]string{"1", "3", "2"}, got); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func Test_handler_Shutdown(t *testing.T) {
+	t.Parallel()
+	t.Run("queued jobs are processed before Shutdown returns", func(t *testing.T) {
+		h, err := New(nil, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mu sync.Mutex
+		var got []string
+		h.WithWorkers(1).WithCommandSpec(CommandSpec{Name: "/HELP"},
+			func(ctx context.Context, payload github.IssueCommentPayload, args Args) error {
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, payload.Repository.FullName)
+				return nil
+			})
+		now := time.Now()
+		for _, j := range []Job{newTestJob("1", "org/a", now), newTestJob("2", "org/a", now), newTestJob("3", "org/b", now)} {
+			if err := h.store.Put(j); err != nil {
+				t.Fatal(err)
+			}
+		}
+		go h.RunBackground(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := h.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(got) != 3 {
+			t.Errorf("processed %d job(s), want 3", len(got))
+		}
+	})
+}
+
+// slowFeedback blocks AddReaction until ctx is done or release is closed
+type slowFeedback struct {
+	release chan struct{}
+	mu      sync.Mutex
+	got     []string
+}
+
+func (f *slowFeedback) AddReaction(ctx context.Context, subjectId, content string) error {
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.got = append(f.got, content)
+	return nil
+}
+
+func (f *slowFeedback) CreateIssueComment(ctx context.Context, org, repo string, prNum int, body string) error {
+	return nil
+}
+
+func Test_handler_Shutdown_feedback(t *testing.T) {
+	t.Parallel()
+	newHandler := func(t *testing.T, feedback Feedback) *handler {
+		h, err := New(nil, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.WithFeedback(feedback).WithCommandSpec(CommandSpec{Name: "/HELP"},
+			func(ctx context.Context, payload github.IssueCommentPayload, args Args) error { return nil })
+		go h.RunBackground(context.Background())
+		var payload github.IssueCommentPayload
+		payload.Action = "created"
+		payload.Comment.Body = "/HELP"
+		payload.Comment.AuthorAssociation = "OWNER"
+		if ok, err := h.enqueue(payload, "1"); !ok || err != nil {
+			t.Fatalf("enqueue() = %v, %v", ok, err)
+		}
+		return h
+	}
+
+	t.Run("Shutdown waits for the reaction on receipt", func(t *testing.T) {
+		feedback := &slowFeedback{release: make(chan struct{})}
+		h := newHandler(t, feedback)
+		time.AfterFunc(10*time.Millisecond, func() { close(feedback.release) })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := h.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		feedback.mu.Lock()
+		defer feedback.mu.Unlock()
+		if !slices.Contains(feedback.got, ReactionEyes) {
+			t.Errorf("reactions %v must contain %s", feedback.got, ReactionEyes)
+		}
+	})
+	t.Run("the reaction is canceled if Shutdown times out", func(t *testing.T) {
+		feedback := &slowFeedback{release: make(chan struct{})}
+		h := newHandler(t, feedback)
+		// release the reaction on success by the worker
+		defer close(feedback.release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := h.Shutdown(ctx); err == nil {
+			t.Fatal("Shutdown() must return the error of ctx")
+		}
+		// the reaction on receipt returns by the cancellation
+		h.feedbackWg.Wait()
+	})
+}
@@ -0,0 +1,57 @@
+package cosme
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+func (e transientError) Unwrap() error { return e.err }
+
+// Transient marks err as transient so that the job is retried.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return transientError{err}
+}
+
+// IsMarkedTransient reports whether err is marked by Transient. It is the classifier
+// for handlers which are not idempotent, so that jobs are retried only if the handler
+// knows that nothing has been changed.
+func IsMarkedTransient(err error) bool {
+	var te transientError
+	return errors.As(err, &te)
+}
+
+// IsTransient is the default classifier used to decide whether a failed job
+// should be retried.
+func IsTransient(err error) bool {
+	if IsMarkedTransient(err) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// backoff returns the interval before the n-th retry (n >= 1).
+func backoff(base time.Duration, n int) time.Duration {
+	const maxBackoff = 5 * time.Minute
+	d := base
+	for i := 1; i < n; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
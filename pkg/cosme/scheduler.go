@@ -0,0 +1,74 @@
+package cosme
+
+import "sync"
+
+// scheduler is an unbounded job queue which never hands out two jobs having
+// the same key at the same time, so jobs for the same repository are
+// processed one by one in order of arrival.
+type scheduler struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	runnable []Job
+	waiting  map[string][]Job
+	running  map[string]struct{}
+	closed   bool
+}
+
+func newScheduler() *scheduler {
+	s := &scheduler{
+		waiting: make(map[string][]Job),
+		running: make(map[string]struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *scheduler) push(j Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.running[j.key()]; ok {
+		s.waiting[j.key()] = append(s.waiting[j.key()], j)
+		return
+	}
+	s.running[j.key()] = struct{}{}
+	s.runnable = append(s.runnable, j)
+	s.cond.Signal()
+}
+
+// pop blocks until a job is runnable. It returns false if the scheduler is
+// closed and no runnable job remains.
+func (s *scheduler) pop() (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.runnable) == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if len(s.runnable) == 0 {
+		return Job{}, false
+	}
+	j := s.runnable[0]
+	s.runnable = s.runnable[1:]
+	return j, true
+}
+
+// done must be called after the job returned by pop was processed.
+func (s *scheduler) done(j Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := j.key()
+	if w := s.waiting[key]; len(w) != 0 {
+		s.runnable = append(s.runnable, w[0])
+		s.waiting[key] = w[1:]
+		s.cond.Signal()
+		return
+	}
+	delete(s.waiting, key)
+	delete(s.running, key)
+}
+
+func (s *scheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
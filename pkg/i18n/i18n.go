@@ -0,0 +1,82 @@
+// Package i18n provides the message catalog translated into the supported languages
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Lang is the language of messages
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+
+	// Default is used if the language is not specified
+	Default = Japanese
+)
+
+// Langs is the list of the supported languages
+var Langs = []Lang{Japanese, English}
+
+// Parse returns the supported language of tag such as "en", "en-US" or "ja_JP".
+// ok is false if the language is not supported.
+func Parse(tag string) (lang Lang, ok bool) {
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	for _, l := range Langs {
+		if strings.EqualFold(base, string(l)) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Catalog maps the key of each message to its format for each language
+type Catalog map[string]map[Lang]string
+
+// Sprintf formats the message of key in lang. If the message is not translated
+// into lang, the one in Default is used, and then key itself.
+func (c Catalog) Sprintf(lang Lang, key string, args ...any) string {
+	formats := c[key]
+	format, ok := formats[lang]
+	if !ok {
+		if format, ok = formats[Default]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Missing returns "<key> (<lang>)" of the messages not translated into some of Langs
+func (c Catalog) Missing() []string {
+	var missing []string
+	for key, formats := range c {
+		for _, lang := range Langs {
+			if _, ok := formats[lang]; !ok {
+				missing = append(missing, fmt.Sprintf("%s (%s)", key, lang))
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+type contextKey struct{}
+
+func IntoContext(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language in ctx, or Default if ctx has no language
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
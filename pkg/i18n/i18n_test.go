@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tag      string
+		expected Lang
+		ok       bool
+	}{
+		{"ja", Japanese, true},
+		{"ja-JP", Japanese, true},
+		{"en-US", English, true},
+		{"en_GB", English, true},
+		{"EN", English, true},
+		{"fr-FR", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.tag, func(t *testing.T) {
+			t.Parallel()
+			lang, ok := Parse(tt.tag)
+			if lang != tt.expected || ok != tt.ok {
+				t.Errorf("Parse(%q) = (%q, %v), want (%q, %v)", tt.tag, lang, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCatalog_Sprintf(t *testing.T) {
+	t.Parallel()
+	c := Catalog{
+		"hello":  {Japanese: "こんにちは %s", English: "Hello %s"},
+		"jaOnly": {Japanese: "日本語のみ"},
+	}
+	tests := []struct {
+		name     string
+		lang     Lang
+		key      string
+		args     []any
+		expected string
+	}{
+		{"translated", English, "hello", []any{"seaman"}, "Hello seaman"},
+		{"fallback to Default", English, "jaOnly", nil, "日本語のみ"},
+		{"unknown key", English, "unknown", nil, "unknown"},
+		{"unknown lang", Lang("fr"), "hello", []any{"seaman"}, "こんにちは seaman"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := c.Sprintf(tt.lang, tt.key, tt.args...); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalog_Missing(t *testing.T) {
+	t.Parallel()
+	c := Catalog{
+		"both":   {Japanese: "a", English: "a"},
+		"jaOnly": {Japanese: "a"},
+		"enOnly": {English: "a"},
+	}
+	expected := []string{"enOnly (ja)", "jaOnly (en)"}
+	if diff := cmp.Diff(expected, c.Missing()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+	if lang := FromContext(context.Background()); lang != Default {
+		t.Errorf("got %q, want Default", lang)
+	}
+	if lang := FromContext(IntoContext(context.Background(), English)); lang != English {
+		t.Errorf("got %q, want %q", lang, English)
+	}
+}
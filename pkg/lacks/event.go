@@ -0,0 +1,165 @@
+package lacks
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+const defaultTimeout = 5 * time.Minute
+
+// keys of the logger passed to callbacks
+const (
+	KeyRequestId = "requestId"
+	KeyUserId    = "userId"
+	KeyChannelId = "channelId"
+	KeyMessageTs = "messageTs"
+	KeyCommand   = "command"
+)
+
+// ErrorHandler is called when a callback returns an error or panics, e.g. for reporting to Slack.
+// The error is always logged before that.
+type ErrorHandler func(ctx context.Context, client *socketmode.Client, evt Event, err error)
+
+// ContextFunc derives the Context passed to callbacks and ErrorHandler from ctx,
+// e.g. for storing the language of the user.
+type ContextFunc func(ctx context.Context, client *socketmode.Client, evt Event) context.Context
+
+// WithTimeout sets the deadline of the Context passed to each callback (5 minutes by default)
+func (r *router) WithTimeout(timeout time.Duration) *router {
+	if timeout > 0 {
+		r.timeout = timeout
+	}
+	return r
+}
+
+// WithErrorHandler sets ErrorHandler. Panics are recovered and passed to it as errors.
+func (r *router) WithErrorHandler(handler ErrorHandler) *router {
+	r.errorHandler = handler
+	return r
+}
+
+// WithContextFunc sets ContextFunc called before each callback
+func (r *router) WithContextFunc(fn ContextFunc) *router {
+	r.contextFunc = fn
+	return r
+}
+
+// Event identifies the event passed to a callback
+type Event struct {
+	// Kind is "command" for mentioned messages, otherwise the type of the interaction
+	Kind string
+	// Name is the command or the ID of the action/callback
+	Name      string
+	RequestId string
+	UserId    string
+	// ChannelId and MessageTs are of the message on which the event occurred (may be empty)
+	ChannelId string
+	MessageTs string
+	// ThreadTs is the timestamp of the parent message if the message is in a thread
+	ThreadTs string
+}
+
+// IsCommand reports whether the event is a mentioned message
+func (e Event) IsCommand() bool {
+	return e.Kind == kindCommand
+}
+
+// ThreadRoot returns the timestamp of the message to which replies are threaded
+func (e Event) ThreadRoot() string {
+	if e.ThreadTs != "" {
+		return e.ThreadTs
+	}
+	return e.MessageTs
+}
+
+type eventKey struct{}
+
+// EventFromContext returns the Event of the Context passed to callbacks
+func EventFromContext(ctx context.Context) (Event, bool) {
+	evt, ok := ctx.Value(eventKey{}).(Event)
+	return evt, ok
+}
+
+type event struct {
+	Event
+	// logAttrs are additional key-value pairs of the logger
+	logAttrs []any
+}
+
+func interactionEvent(evt *socketmode.Event, kind, name string, interaction slack.InteractionCallback) event {
+	return event{Event: Event{
+		Kind:      kind,
+		Name:      name,
+		RequestId: evt.Request.EnvelopeID,
+		UserId:    interaction.User.ID,
+		ChannelId: interaction.Channel.ID,
+		MessageTs: interaction.Container.MessageTs,
+		ThreadTs:  interaction.Container.ThreadTs,
+	}}
+}
+
+// call calls fn with a fresh Context for the event in the span named "<kind> <name>",
+// and records the result. Panics in fn are recovered and reported as errors.
+func (r *router) call(client *socketmode.Client, evt event, fn func(context.Context) error) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, eventKey{}, evt.Event)
+	ctx = log.IntoContext(ctx, r.log.With(
+		KeyRequestId, evt.RequestId,
+		KeyUserId, evt.UserId,
+		KeyChannelId, evt.ChannelId,
+		KeyMessageTs, evt.MessageTs,
+		KeyCommand, evt.Name,
+	).With(evt.logAttrs...))
+	ctx, span := tracer.Start(ctx, evt.Kind+" "+evt.Name,
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attrRequestId.String(evt.RequestId),
+			attrUserId.String(evt.UserId),
+			attrChannelId.String(evt.ChannelId),
+			attrMessageTs.String(evt.MessageTs),
+		))
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+			log.FromContext(ctx).Error(err.Error(), "stack", string(debug.Stack()))
+		} else if err != nil {
+			log.FromContext(ctx).Error(err.Error(), log.KeyDetail, err)
+		}
+		if err != nil && r.errorHandler != nil {
+			// report even if the deadline is exceeded
+			r.errorHandler(context.WithoutCancel(ctx), client, evt.Event, err)
+		}
+		if r.observer != nil {
+			r.observer(evt.Kind, evt.Name, time.Since(start), err)
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+	if r.contextFunc != nil {
+		ctx = r.contextFunc(ctx, client, evt.Event)
+	}
+	return fn(ctx)
+}
+
+// attributes of spans
+const (
+	attrRequestId = attribute.Key("slack.request_id")
+	attrUserId    = attribute.Key("slack.user_id")
+	attrChannelId = attribute.Key("slack.channel_id")
+	attrMessageTs = attribute.Key("slack.message_ts")
+)
@@ -0,0 +1,154 @@
+package lacks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"golang.org/x/exp/slog"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/log"
+)
+
+// syncBuffer is bytes.Buffer which can be written concurrently
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+func newTestRouter(buf *syncBuffer) (*router, *socketmode.Client) {
+	client := socketmode.New(slack.New("xoxb-dummy"))
+	return NewRouter(slog.New(slog.NewJSONHandler(buf, nil)), client), client
+}
+
+func Test_router_call(t *testing.T) {
+	t.Parallel()
+
+	t.Run("each event has its own logger and deadline", func(t *testing.T) {
+		buf := &syncBuffer{}
+		r, client := newTestRouter(buf)
+		r.WithTimeout(time.Minute)
+
+		var wg sync.WaitGroup
+		for _, id := range []string{"U1", "U2", "U3"} {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				_ = r.call(client, event{Event: Event{Kind: kindCommand, Name: "test", RequestId: "req-" + id, UserId: id}},
+					func(ctx context.Context) error {
+						if _, ok := ctx.Deadline(); !ok {
+							t.Error("ctx has no deadline")
+						}
+						if evt, _ := EventFromContext(ctx); evt.UserId != id {
+							t.Errorf("EventFromContext().UserId = %q, want %q", evt.UserId, id)
+						}
+						log.FromContext(ctx).Info("handled")
+						return nil
+					})
+			}(id)
+		}
+		wg.Wait()
+
+		for _, record := range buf.records(t) {
+			if diff := cmp.Diff("req-"+record[KeyUserId].(string), record[KeyRequestId]); diff != "" {
+				t.Error(diff)
+			}
+		}
+	})
+	t.Run("panic is recovered and reported", func(t *testing.T) {
+		buf := &syncBuffer{}
+		r, client := newTestRouter(buf)
+		var reported []Event
+		var reportedErr error
+		r.WithErrorHandler(func(ctx context.Context, _ *socketmode.Client, evt Event, err error) {
+			reported = append(reported, evt)
+			reportedErr = err
+		})
+		var observed error
+		r.WithObserver(func(_, _ string, _ time.Duration, err error) { observed = err })
+
+		evt := Event{Kind: kindCommand, Name: "test", ChannelId: "C1", MessageTs: "1.2"}
+		err := r.call(client, event{Event: evt}, func(ctx context.Context) error {
+			panic("boom")
+		})
+		if err == nil {
+			t.Fatal("error is not returned")
+		}
+		if diff := cmp.Diff([]Event{evt}, reported); diff != "" {
+			t.Error(diff)
+		}
+		if !errors.Is(reportedErr, err) || !strings.Contains(err.Error(), "boom") {
+			t.Errorf("reported %v, want %v", reportedErr, err)
+		}
+		if !errors.Is(observed, err) {
+			t.Errorf("observed %v, want %v", observed, err)
+		}
+	})
+	t.Run("error handler is called only if callback fails", func(t *testing.T) {
+		buf := &syncBuffer{}
+		r, client := newTestRouter(buf)
+		var reported []error
+		r.WithErrorHandler(func(ctx context.Context, _ *socketmode.Client, _ Event, err error) {
+			if ctx.Err() != nil {
+				t.Error("ctx is canceled")
+			}
+			reported = append(reported, err)
+		})
+
+		errFailed := errors.New("failed")
+		_ = r.call(client, event{}, func(ctx context.Context) error { return nil })
+		_ = r.call(client, event{}, func(ctx context.Context) error { return errFailed })
+		if diff := cmp.Diff([]error{errFailed}, reported, cmpopts.EquateErrors()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("context func derives the context of callback and error handler", func(t *testing.T) {
+		buf := &syncBuffer{}
+		r, client := newTestRouter(buf)
+		r.WithContextFunc(func(ctx context.Context, _ *socketmode.Client, evt Event) context.Context {
+			return context.WithValue(ctx, contextFuncKey{}, evt.UserId)
+		})
+		var values []any
+		r.WithErrorHandler(func(ctx context.Context, _ *socketmode.Client, _ Event, _ error) {
+			values = append(values, ctx.Value(contextFuncKey{}))
+		})
+
+		_ = r.call(client, event{Event: Event{UserId: "U1"}}, func(ctx context.Context) error {
+			values = append(values, ctx.Value(contextFuncKey{}))
+			return errors.New("failed")
+		})
+		if diff := cmp.Diff([]any{"U1", "U1"}, values); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+type contextFuncKey struct{}
@@ -0,0 +1,35 @@
+package lacks
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+type funcBlockSuggestion func(context.Context, slack.InteractionCallback, *socketmode.Client) ([]*slack.OptionBlockObject, error)
+
+// HandleBlockSuggestion registers the callback returning options of the external_select whose action_id is actionID.
+// interaction.Value is the text typed by the user. The options are returned in the acknowledgement,
+// so the callback must return within 3 seconds. If it fails, no option is shown.
+func (h *router) HandleBlockSuggestion(actionID string, callback funcBlockSuggestion) {
+	h.handleInteraction(slack.InteractionTypeBlockSuggestion, actionID, func(evt *socketmode.Event, client *socketmode.Client, interaction slack.InteractionCallback) {
+		if !h.begin() {
+			client.Ack(*evt.Request, slack.OptionsResponse{Options: []*slack.OptionBlockObject{}})
+			return
+		}
+		defer h.end()
+		e := interactionEvent(evt, string(slack.InteractionTypeBlockSuggestion), actionID, interaction)
+		e.logAttrs = []any{"input", interaction.Value}
+
+		var options []*slack.OptionBlockObject
+		if err := h.call(client, e, func(ctx context.Context) error {
+			var err error
+			options, err = callback(ctx, interaction, client)
+			return err
+		}); err != nil {
+			options = []*slack.OptionBlockObject{}
+		}
+		client.Ack(*evt.Request, slack.OptionsResponse{Options: options})
+	})
+}
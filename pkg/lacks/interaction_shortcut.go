@@ -0,0 +1,17 @@
+package lacks
+
+import (
+	"github.com/slack-go/slack"
+)
+
+// HandleShortcut registers the callback for the global shortcut whose callback_id is callbackID.
+// interaction.TriggerID is available for opening a modal.
+func (h *router) HandleShortcut(callbackID string, callback funcInteractionCallback) {
+	h.handleInteractionByCallbackID(slack.InteractionTypeShortcut, callbackID, callback)
+}
+
+// HandleMessageShortcut registers the callback for the message shortcut whose callback_id is callbackID.
+// interaction.Message and interaction.Channel are the message on which the shortcut is used.
+func (h *router) HandleMessageShortcut(callbackID string, callback funcInteractionCallback) {
+	h.handleInteractionByCallbackID(slack.InteractionTypeMessageAction, callbackID, callback)
+}
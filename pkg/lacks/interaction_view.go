@@ -0,0 +1,67 @@
+package lacks
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+type funcViewSubmission func(context.Context, slack.InteractionCallback, *socketmode.Client) (*slack.ViewSubmissionResponse, error)
+
+type afterAckKey struct{}
+
+// HandleViewSubmission registers the callback for submitting the modal whose callback_id is callbackID.
+// The response returned by the callback (e.g. slack.NewErrorsViewSubmissionResponse) is sent in the
+// acknowledgement, so the callback must return within 3 seconds. If it is nil, the modal is closed.
+// Slow work should be registered by AfterAck, which is called after the acknowledgement.
+func (h *router) HandleViewSubmission(callbackID string, callback funcViewSubmission) {
+	h.handleInteraction(slack.InteractionTypeViewSubmission, callbackID, func(evt *socketmode.Event, client *socketmode.Client, interaction slack.InteractionCallback) {
+		if !h.begin() {
+			client.Ack(*evt.Request)
+			return
+		}
+		defer h.end()
+
+		acked := false
+		_ = h.call(client, interactionEvent(evt, string(slack.InteractionTypeViewSubmission), callbackID, interaction),
+			func(ctx context.Context) error {
+				var afterAck []func(context.Context) error
+				resp, err := callback(context.WithValue(ctx, afterAckKey{}, &afterAck), interaction, client)
+				if resp != nil {
+					client.Ack(*evt.Request, resp)
+				} else {
+					client.Ack(*evt.Request)
+				}
+				acked = true
+				if err != nil {
+					return err
+				}
+				for _, fn := range afterAck {
+					if err := fn(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		if !acked {
+			// the callback panicked
+			client.Ack(*evt.Request)
+		}
+	})
+}
+
+// AfterAck registers fn called with ctx after the view submission is acknowledged.
+// It must be called in callbacks of HandleViewSubmission, otherwise fn is ignored.
+// The error of fn is handled in the same way as the error of the callback.
+func AfterAck(ctx context.Context, fn func(context.Context) error) {
+	if afterAck, ok := ctx.Value(afterAckKey{}).(*[]func(context.Context) error); ok {
+		*afterAck = append(*afterAck, fn)
+	}
+}
+
+// HandleViewClosed registers the callback for closing the modal whose callback_id is callbackID.
+// It is called only if the modal is opened with notify_on_close.
+func (h *router) HandleViewClosed(callbackID string, callback funcInteractionCallback) {
+	h.handleInteractionByCallbackID(slack.InteractionTypeViewClosed, callbackID, callback)
+}
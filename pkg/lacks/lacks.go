@@ -1,28 +1,123 @@
 package lacks
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/socketmode"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/exp/slog"
+
+	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 )
 
+// Observer is called with the result of each callback (e.g. for metrics).
+// kind is "command" for mentioned messages, otherwise the type of the interaction.
+type Observer func(kind, name string, duration time.Duration, err error)
+
+const kindCommand = "command"
+
+var tracer = otel.Tracer("github.com/cloudnativedaysjp/seaman/pkg/lacks")
+
 type router struct {
 	commands          []command
 	log               *slog.Logger
 	socketmodeHandler *socketmode.SocketmodeHandler
+	observer          Observer
+	errorHandler      ErrorHandler
+	contextFunc       ContextFunc
+	timeout           time.Duration
+	connected         atomic.Bool
+	interactions      map[slack.InteractionType]map[string]interactionHandler
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
 }
 
 func NewRouter(logger *slog.Logger, client *socketmode.Client) *router {
-	return &router{
-		[]command{},
-		logger,
-		socketmode.NewSocketmodeHandler(client),
+	r := &router{
+		commands:          []command{},
+		log:               logger,
+		socketmodeHandler: socketmode.NewSocketmodeHandler(client),
+		interactions:      map[slack.InteractionType]map[string]interactionHandler{},
+		timeout:           defaultTimeout,
+	}
+	r.socketmodeHandler.Handle(socketmode.EventTypeConnected, r.setConnected(true))
+	for _, et := range []socketmode.EventType{
+		socketmode.EventTypeConnecting,
+		socketmode.EventTypeConnectionError,
+		socketmode.EventTypeInvalidAuth,
+	} {
+		r.socketmodeHandler.Handle(et, r.setConnected(false))
+	}
+	return r
+}
+
+// WithObserver sets Observer called after each callback returns
+func (r *router) WithObserver(observer Observer) *router {
+	r.observer = observer
+	return r
+}
+
+// Connected returns true while the Socket Mode connection is established
+func (r *router) Connected() bool {
+	return r.connected.Load()
+}
+
+func (r *router) setConnected(connected bool) socketmode.SocketmodeHandlerFunc {
+	return func(*socketmode.Event, *socketmode.Client) {
+		r.connected.Store(connected)
+	}
+}
+
+// RunEventLoop receives events until ctx is done
+func (r *router) RunEventLoop(ctx context.Context) error {
+	if err := r.socketmodeHandler.RunEventLoopContext(ctx); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// Shutdown ignores events received after it is called and waits until
+// in-flight handlers return. If ctx is done before that, it returns ctx.Err().
+func (r *router) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin must be called before calling handlers, and end must be called
+// after that if begin returns true
+func (r *router) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
 	}
+	r.inflight.Add(1)
+	return true
 }
 
-func (r *router) RunEventLoop() error {
-	return r.socketmodeHandler.RunEventLoop()
+func (r *router) end() {
+	r.inflight.Done()
 }
 
 type command struct {
@@ -33,3 +128,72 @@ type command struct {
 func (c command) prefix() string {
 	return strings.Join(c.prefixes, " ")
 }
+
+// interactionHandler must acknowledge evt
+type interactionHandler func(*socketmode.Event, *socketmode.Client, slack.InteractionCallback)
+
+// handleInteraction registers the handler for interactions of the type whose id is id.
+// The id is action_id for block_suggestion, callback_id of the view for view_submission
+// and view_closed, otherwise callback_id.
+// Interactions which no handler matches are acknowledged so that Slack does not retry them.
+func (h *router) handleInteraction(it slack.InteractionType, id string, handler interactionHandler) {
+	handlers, ok := h.interactions[it]
+	if !ok {
+		handlers = map[string]interactionHandler{}
+		h.interactions[it] = handlers
+		h.socketmodeHandler.HandleInteraction(it, h.dispatchInteraction(it, handlers))
+	}
+	handlers[id] = handler
+}
+
+func (h *router) dispatchInteraction(it slack.InteractionType, handlers map[string]interactionHandler) socketmode.SocketmodeHandlerFunc {
+	return func(evt *socketmode.Event, client *socketmode.Client) {
+		interaction, err := utils.GetInteractionCallback(evt)
+		if err != nil {
+			h.log.Error(fmt.Sprintf("failed to get InteractionCallback: %v", err))
+			ackInteraction(client, evt, it)
+			return
+		}
+		var id string
+		switch it {
+		case slack.InteractionTypeBlockSuggestion:
+			id = interaction.ActionID
+		case slack.InteractionTypeViewSubmission, slack.InteractionTypeViewClosed:
+			id = interaction.View.CallbackID
+		default:
+			id = interaction.CallbackID
+		}
+		handler, ok := handlers[id]
+		if !ok {
+			ackInteraction(client, evt, it)
+			return
+		}
+		handler(evt, client, interaction)
+	}
+}
+
+// ackInteraction acknowledges evt with an empty payload
+func ackInteraction(client *socketmode.Client, evt *socketmode.Event, it slack.InteractionType) {
+	if it == slack.InteractionTypeBlockSuggestion {
+		client.Ack(*evt.Request, slack.OptionsResponse{Options: []*slack.OptionBlockObject{}})
+		return
+	}
+	client.Ack(*evt.Request)
+}
+
+// handleInteractionByCallbackID registers the callback for interactions of the type
+// whose callback_id (or callback_id of the view) is callbackID
+func (h *router) handleInteractionByCallbackID(it slack.InteractionType, callbackID string, callback funcInteractionCallback) {
+	h.handleInteraction(it, callbackID, func(evt *socketmode.Event, client *socketmode.Client, interaction slack.InteractionCallback) {
+		client.Ack(*evt.Request)
+		if !h.begin() {
+			return
+		}
+		defer h.end()
+
+		_ = h.call(client, interactionEvent(evt, string(it), callbackID, interaction),
+			func(ctx context.Context) error {
+				return callback(ctx, interaction, client)
+			})
+	})
+}
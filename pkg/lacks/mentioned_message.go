@@ -8,41 +8,66 @@ import (
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 
-	"github.com/cloudnativedaysjp/seaman/pkg/log"
 	"github.com/cloudnativedaysjp/seaman/pkg/utils"
 )
 
 type funcAppMentionEvent func(context.Context, *slackevents.AppMentionEvent, *socketmode.Client) error
 
 func (h *router) HandleMentionedMessage(c string, callback funcAppMentionEvent) OptBuilderMentionedMessage {
-	ctx := context.Background()
 	h.commands = append(h.commands, command{prefixes: strings.Fields(c)})
 
 	h.socketmodeHandler.HandleEvents(slackevents.AppMention, func(evt *socketmode.Event, client *socketmode.Client) {
-		ev, err := utils.GetAppMentionEvent(evt)
-		inputCmds := strings.Join(strings.Fields(ev.Text)[1:], " ")
-		if !strings.HasPrefix(inputCmds, c) {
+		ev, inputCmds, ok := h.matchMentionedMessage(evt, c)
+		if !ok {
 			return
 		}
 
 		client.Ack(*evt.Request)
-
-		if err != nil {
-			h.log.Error(fmt.Sprintf("failed to get InteractionCallback: %v", err))
+		if !h.begin() {
 			return
 		}
-		ctx = log.IntoContext(ctx, h.log.
-			With("messageTs", ev.TimeStamp).
-			With("input", inputCmds),
-		)
+		defer h.end()
 
-		if err := callback(ctx, ev, client); err != nil {
-			h.log.Error(err.Error(), log.KeyDetail, err)
-		}
+		e := mentionEvent(evt, c, ev)
+		e.logAttrs = []any{"input", inputCmds}
+		_ = h.call(client, e, func(ctx context.Context) error {
+			return callback(ctx, ev, client)
+		})
 	})
 	return OptBuilderMentionedMessage{h, c}
 }
 
+// matchMentionedMessage returns the event and the text without the mention
+// if the text starts with prefix
+func (h *router) matchMentionedMessage(evt *socketmode.Event, prefix string) (*slackevents.AppMentionEvent, string, bool) {
+	ev, err := utils.GetAppMentionEvent(evt)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("failed to get AppMentionEvent: %v", err))
+		return nil, "", false
+	}
+	fields := strings.Fields(ev.Text)
+	if len(fields) == 0 {
+		return nil, "", false
+	}
+	inputCmds := strings.Join(fields[1:], " ")
+	if !strings.HasPrefix(inputCmds, prefix) {
+		return nil, "", false
+	}
+	return ev, inputCmds, true
+}
+
+func mentionEvent(evt *socketmode.Event, command string, ev *slackevents.AppMentionEvent) event {
+	return event{Event: Event{
+		Kind:      kindCommand,
+		Name:      command,
+		RequestId: evt.Request.EnvelopeID,
+		UserId:    ev.User,
+		ChannelId: ev.Channel,
+		MessageTs: ev.TimeStamp,
+		ThreadTs:  ev.ThreadTimeStamp,
+	}}
+}
+
 type OptBuilderMentionedMessage struct {
 	owner   *router
 	command string
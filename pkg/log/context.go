@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 )
 
@@ -13,11 +14,16 @@ func IntoContext(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, contextKey{}, logger)
 }
 
+// FromContext returns the logger in ctx. If ctx has a span,
+// the trace ID and span ID are added to the logger.
 func FromContext(ctx context.Context, keysAndValues ...any) *slog.Logger {
 	logger, ok := ctx.Value(contextKey{}).(*slog.Logger)
 	if !ok {
 		// if failed to get from context, get logger with default setting
-		return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With(KeyTraceId, sc.TraceID().String(), KeySpanId, sc.SpanID().String())
 	}
 	return logger
 }
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+	logged := func(t *testing.T, ctx context.Context) map[string]any {
+		t.Helper()
+		FromContext(ctx).Info("hello")
+		buf := ctx.Value(bufferKey{}).(*bytes.Buffer)
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		delete(record, slog.TimeKey)
+		return record
+	}
+	newContext := func() context.Context {
+		buf := &bytes.Buffer{}
+		ctx := context.WithValue(context.Background(), bufferKey{}, buf)
+		return IntoContext(ctx, slog.New(slog.NewJSONHandler(buf, nil)))
+	}
+
+	t.Run("trace ID and span ID are added if ctx has a span", func(t *testing.T) {
+		traceId, _ := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+		spanId, _ := trace.SpanIDFromHex("0123456789abcdef")
+		ctx := trace.ContextWithSpanContext(newContext(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceId, SpanID: spanId,
+		}))
+		expected := map[string]any{
+			"level": "INFO", "msg": "hello",
+			KeyTraceId: "0123456789abcdef0123456789abcdef", KeySpanId: "0123456789abcdef",
+		}
+		if diff := cmp.Diff(expected, logged(t, ctx)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("nothing is added if ctx has no span", func(t *testing.T) {
+		expected := map[string]any{"level": "INFO", "msg": "hello"}
+		if diff := cmp.Diff(expected, logged(t, newContext())); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+type bufferKey struct{}
@@ -2,4 +2,7 @@ package log
 
 const (
 	KeyDetail = "detail"
+	// KeyTraceId and KeySpanId are added by FromContext if ctx has a span
+	KeyTraceId = "traceId"
+	KeySpanId  = "spanId"
 )
@@ -28,10 +28,18 @@ func GetInteractionCallback(evt *socketmode.Event) (slack.InteractionCallback, e
 	return interaction, nil
 }
 
+// GetCallbackValueOnStaticSelect returns the selected value, or empty if the interaction has no block action
 func GetCallbackValueOnStaticSelect(i slack.InteractionCallback) string {
+	if len(i.ActionCallback.BlockActions) == 0 {
+		return ""
+	}
 	return i.ActionCallback.BlockActions[0].SelectedOption.Value
 }
 
+// GetCallbackValueOnButton returns the value of the button, or empty if the interaction has no block action
 func GetCallbackValueOnButton(i slack.InteractionCallback) string {
+	if len(i.ActionCallback.BlockActions) == 0 {
+		return ""
+	}
 	return i.ActionCallback.BlockActions[0].Value
 }